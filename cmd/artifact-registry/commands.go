@@ -0,0 +1,137 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Commands of the CLI
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+	registry "github.com/Vernacular-ai/artifact-registry/registry"
+)
+
+// Number of results fetched per call to MLMD when listing
+const pageSize = 100
+
+func listWorkspaces(ctx context.Context, store registry.MLArtifactStore, cfg config, args []string) (output, error) {
+	var workspaces []registry.Workspace
+
+	options := registry.ListOptions{PageSize: pageSize}
+	for {
+		page, nextPageToken, err := store.ListWorkspacesWithContext(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		workspaces = append(workspaces, page...)
+		if nextPageToken == "" {
+			return workspacesOutput(workspaces), nil
+		}
+		options.PageToken = nextPageToken
+	}
+}
+
+func listArtifacts(ctx context.Context, store registry.MLArtifactStore, cfg config, args []string) (output, error) {
+	workspace, err := store.GetWorkspaceWithContext(ctx, &pb.Workspace{Name: cfg.workspace})
+	if err != nil {
+		return nil, err
+	}
+
+	options := registry.ListOptions{PageSize: pageSize, OrderBy: registry.OrderByID}
+	var iterator *registry.ArtifactIterator
+	if cfg.artifactType == "" {
+		iterator = workspace.IterateArtifacts(ctx, options)
+	} else {
+		iterator = workspace.IterateArtifactsByType(ctx, artifactTypeRequest(cfg.artifactType), options)
+	}
+
+	artifacts := &pb.ArtifactsResponse{}
+	for iterator.Next() {
+		artifacts.Artifacts = append(artifacts.Artifacts, iterator.Artifact())
+	}
+	if err := iterator.Err(); err != nil {
+		return nil, err
+	}
+	return artifactsOutput{artifacts}, nil
+}
+
+func getArtifacts(ctx context.Context, store registry.MLArtifactStore, cfg config, args []string) (output, error) {
+	ids := make([]int64, len(args))
+	for i, arg := range args {
+		id, err := parseID(arg)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+
+	artifacts, err := store.GetArtifactsByIDWithContext(ctx, &pb.MLArtifact{Ids: ids})
+	if err != nil {
+		return nil, err
+	}
+	if len(artifacts.GetArtifacts()) < len(ids) {
+		return nil, fmt.Errorf("%d of the %d artifacts do not exist: %w", len(ids)-len(artifacts.GetArtifacts()), len(ids), registry.ErrNotFound)
+	}
+	return artifactsOutput{artifacts}, nil
+}
+
+func modelLineage(ctx context.Context, store registry.MLArtifactStore, cfg config, args []string) (output, error) {
+	modelId, err := parseID(args[0])
+	if err != nil {
+		return nil, err
+	}
+	workspace, err := store.GetWorkspaceWithContext(ctx, &pb.Workspace{Name: cfg.workspace})
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts, err := workspace.GetLineageByModelWithContext(ctx, &pb.ArtifactsByModelRequest{ModelId: modelId})
+	if err != nil {
+		return nil, err
+	}
+	return artifactsOutput{artifacts}, nil
+}
+
+func runLineage(ctx context.Context, store registry.MLArtifactStore, cfg config, args []string) (output, error) {
+	workspace, err := store.GetWorkspaceWithContext(ctx, &pb.Workspace{Name: cfg.workspace})
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts, err := workspace.GetLineageByRunWithContext(ctx, &pb.ArtifactsByRunRequest{RunId: args[0]})
+	if err != nil {
+		return nil, err
+	}
+	return artifactsOutput{artifacts}, nil
+}
+
+// artifactTypeRequest requests one of the artifact types of the registry by
+// name, e.g. model, or else an MLMD artifact type.
+func artifactTypeRequest(artifactType string) *pb.ArtifactByTypeRequest {
+	if value, ok := pb.ArtifactByTypeRequest_ArtifactType_value[strings.ToUpper(artifactType)]; ok {
+		return &pb.ArtifactByTypeRequest{ArtifactType: pb.ArtifactByTypeRequest_ArtifactType(value)}
+	}
+	return &pb.ArtifactByTypeRequest{TypeName: artifactType}
+}
+
+func parseID(arg string) (int64, error) {
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid artifact ID %q: %w", arg, registry.ErrInvalidArgument)
+	}
+	return id, nil
+}
@@ -0,0 +1,261 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Command artifact-registry inspects the artifacts stored in MLMD.
+//
+//	artifact-registry workspaces list
+//	artifact-registry artifacts list --workspace workspace_1 --type model
+//	artifact-registry artifacts get 2 -o yaml
+//	artifact-registry lineage model 2 --workspace workspace_1
+//	artifact-registry lineage run run-2021-03-30 --workspace workspace_1
+//
+// The connection to MLMD is configured by flags or by the environment
+// variables named in their description, flags take precedence.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	registry "github.com/Vernacular-ai/artifact-registry/registry"
+)
+
+const usage = `Usage: artifact-registry <command> [flags]
+
+Commands:
+  workspaces list                  List the workspaces
+  artifacts list --workspace NAME  List the artifacts of a workspace, --type
+                                   keeps those of a type: model, metrics,
+                                   dataset or an MLMD type name
+  artifacts get ID...              Show artifacts by ID
+  lineage model ID --workspace NAME
+                                   List the artifacts related to a model
+  lineage run RUN_ID --workspace NAME
+                                   List the artifacts of a Kubeflow run
+
+Run "artifact-registry <command> -h" to list the flags.
+`
+
+// Exit codes
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// config holds the flags of a command.
+type config struct {
+	host       string
+	port       string
+	tls        bool
+	caCert     string
+	clientCert string
+	clientKey  string
+	timeout    time.Duration
+	output     string
+
+	workspace    string
+	artifactType string
+}
+
+// cli runs commands, writing their results to stdout and errors to stderr.
+type cli struct {
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+
+	// Options applied after the ones built from the flags, e.g. to use an
+	// in-memory MLMD
+	storeOptions []registry.Option
+}
+
+func main() {
+	os.Exit(cli{stdout: os.Stdout, stderr: os.Stderr, getenv: os.Getenv}.run(os.Args[1:]))
+}
+
+// run executes the command of args and returns the exit code.
+func (c cli) run(args []string) int {
+	if len(args) < 2 {
+		if len(args) == 1 && (args[0] == "-h" || args[0] == "--help" || args[0] == "help") {
+			fmt.Fprint(c.stdout, usage)
+			return exitOK
+		}
+		fmt.Fprint(c.stderr, usage)
+		return exitUsage
+	}
+
+	name := args[0] + " " + args[1]
+	var handler func(ctx context.Context, store registry.MLArtifactStore, cfg config, args []string) (output, error)
+	var flags func(flagSet *flag.FlagSet, cfg *config)
+	minArgs, maxArgs := 0, 0
+	// Commands taking flags work on a workspace
+	switch name {
+	case "workspaces list":
+		handler = listWorkspaces
+	case "artifacts list":
+		handler, flags = listArtifacts, c.artifactsFlags
+	case "artifacts get":
+		handler, minArgs, maxArgs = getArtifacts, 1, -1
+	case "lineage model":
+		handler, flags, minArgs, maxArgs = modelLineage, c.workspaceFlag, 1, 1
+	case "lineage run":
+		handler, flags, minArgs, maxArgs = runLineage, c.workspaceFlag, 1, 1
+	default:
+		fmt.Fprintf(c.stderr, "artifact-registry: unknown command %q\n\n%s", name, usage)
+		return exitUsage
+	}
+
+	var cfg config
+	flagSet := flag.NewFlagSet("artifact-registry "+name, flag.ContinueOnError)
+	flagSet.SetOutput(c.stderr)
+	c.connectionFlags(flagSet, &cfg)
+	if flags != nil {
+		flags(flagSet, &cfg)
+	}
+	positional, err := parseInterspersed(flagSet, args[2:])
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	if err != nil {
+		return exitUsage
+	}
+	if len(positional) < minArgs || (maxArgs >= 0 && len(positional) > maxArgs) {
+		fmt.Fprintf(c.stderr, "artifact-registry: wrong number of arguments for %s\n\n%s", name, usage)
+		return exitUsage
+	}
+	if flags != nil && cfg.workspace == "" {
+		fmt.Fprintf(c.stderr, "artifact-registry: %s requires --workspace\n", name)
+		return exitUsage
+	}
+	format, err := parseFormat(cfg.output)
+	if err != nil {
+		fmt.Fprintf(c.stderr, "artifact-registry: %v\n", err)
+		return exitUsage
+	}
+
+	store, err := c.artifactStore(cfg)
+	if err != nil {
+		fmt.Fprintf(c.stderr, "artifact-registry: %v\n", err)
+		return exitError
+	}
+	defer store.Close()
+
+	result, err := handler(context.Background(), store, cfg, positional)
+	if err != nil {
+		fmt.Fprintf(c.stderr, "artifact-registry: %v\n", err)
+		return exitError
+	}
+	if err := result.write(c.stdout, format); err != nil {
+		fmt.Fprintf(c.stderr, "artifact-registry: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// connectionFlags declares the flags shared by all commands, defaulting to
+// their environment variable.
+func (c cli) connectionFlags(flagSet *flag.FlagSet, cfg *config) {
+	flagSet.StringVar(&cfg.host, "host", c.env("ARTIFACT_REGISTRY_HOST", "localhost"), "host of the MLMD gRPC server, $ARTIFACT_REGISTRY_HOST")
+	flagSet.StringVar(&cfg.port, "port", c.env("ARTIFACT_REGISTRY_PORT", "8080"), "port of the MLMD gRPC server, $ARTIFACT_REGISTRY_PORT")
+	flagSet.BoolVar(&cfg.tls, "tls", c.boolEnv("ARTIFACT_REGISTRY_TLS"), "connect over TLS, $ARTIFACT_REGISTRY_TLS")
+	flagSet.StringVar(&cfg.caCert, "ca-cert", c.env("ARTIFACT_REGISTRY_CA_CERT", ""), "PEM file of the CAs verifying the server instead of the system ones, implies --tls, $ARTIFACT_REGISTRY_CA_CERT")
+	flagSet.StringVar(&cfg.clientCert, "client-cert", c.env("ARTIFACT_REGISTRY_CLIENT_CERT", ""), "PEM file of the client certificate for mutual TLS, implies --tls, $ARTIFACT_REGISTRY_CLIENT_CERT")
+	flagSet.StringVar(&cfg.clientKey, "client-key", c.env("ARTIFACT_REGISTRY_CLIENT_KEY", ""), "PEM file of the key of the client certificate, $ARTIFACT_REGISTRY_CLIENT_KEY")
+	flagSet.DurationVar(&cfg.timeout, "timeout", c.durationEnv("ARTIFACT_REGISTRY_TIMEOUT", 30*time.Second), "timeout of every call to MLMD, $ARTIFACT_REGISTRY_TIMEOUT")
+	flagSet.StringVar(&cfg.output, "o", c.env("ARTIFACT_REGISTRY_OUTPUT", "table"), "output format: table, json or yaml, $ARTIFACT_REGISTRY_OUTPUT")
+	flagSet.StringVar(&cfg.output, "output", c.env("ARTIFACT_REGISTRY_OUTPUT", "table"), "same as -o")
+}
+
+func (c cli) workspaceFlag(flagSet *flag.FlagSet, cfg *config) {
+	flagSet.StringVar(&cfg.workspace, "workspace", c.env("ARTIFACT_REGISTRY_WORKSPACE", ""), "name of the workspace, $ARTIFACT_REGISTRY_WORKSPACE")
+}
+
+func (c cli) artifactsFlags(flagSet *flag.FlagSet, cfg *config) {
+	c.workspaceFlag(flagSet, cfg)
+	flagSet.StringVar(&cfg.artifactType, "type", "", "keep the artifacts of a type: model, metrics, dataset or an MLMD type name")
+}
+
+func (c cli) env(key string, fallback string) string {
+	if value := strings.TrimSpace(c.getenv(key)); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func (c cli) boolEnv(key string) bool {
+	value, err := strconv.ParseBool(c.env(key, "false"))
+	return err == nil && value
+}
+
+func (c cli) durationEnv(key string, fallback time.Duration) time.Duration {
+	if value, err := time.ParseDuration(c.env(key, "")); err == nil {
+		return value
+	}
+	return fallback
+}
+
+// artifactStore connects to MLMD as configured.
+func (c cli) artifactStore(cfg config) (registry.MLArtifactStore, error) {
+	options := []registry.Option{registry.WithAddress(cfg.host, cfg.port), registry.WithTimeout(cfg.timeout)}
+	if cfg.tls {
+		options = append(options, registry.WithTLS(nil))
+	}
+	if cfg.caCert != "" {
+		caPEM, err := ioutil.ReadFile(cfg.caCert)
+		if err != nil {
+			return registry.MLArtifactStore{}, err
+		}
+		options = append(options, registry.WithCustomCA(caPEM))
+	}
+	if cfg.clientCert != "" || cfg.clientKey != "" {
+		certPEM, err := ioutil.ReadFile(cfg.clientCert)
+		if err != nil {
+			return registry.MLArtifactStore{}, err
+		}
+		keyPEM, err := ioutil.ReadFile(cfg.clientKey)
+		if err != nil {
+			return registry.MLArtifactStore{}, err
+		}
+		options = append(options, registry.WithClientCertificate(certPEM, keyPEM))
+	}
+	return registry.NewArtifactStore(append(options, c.storeOptions...)...)
+}
+
+// parseInterspersed parses flags placed before, between or after the
+// positional arguments, which it returns.
+func parseInterspersed(flagSet *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flagSet.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := flagSet.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			// Everything after -- is positional
+			return append(positional, rest...), nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
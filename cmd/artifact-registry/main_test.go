@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+	registry "github.com/Vernacular-ai/artifact-registry/registry"
+	"github.com/Vernacular-ai/artifact-registry/registry/mlmdtest"
+)
+
+func exampleMLMD() *mlmdtest.Store {
+	mlmd := mlmdtest.NewStore()
+	mlmd.SeedKubeflowTypes()
+
+	run := "run-2021-03-30T16:50:45.608098"
+	dataset := mlmd.SeedArtifact(mlmdtest.Artifact{
+		Type:      mlmdtest.DatasetType,
+		Name:      "mnist",
+		Version:   "dataset_version_1",
+		URI:       "gcs://my-bucket/mnist-data",
+		Workspace: "workspace_1",
+		RunID:     run,
+	})
+	model := mlmd.SeedArtifact(mlmdtest.Artifact{
+		Type:      mlmdtest.ModelType,
+		Name:      "MNIST",
+		Version:   "1.0",
+		URI:       "gcs://my-bucket/mnist",
+		Workspace: "workspace_1",
+		RunID:     run,
+	})
+	mlmd.SeedExecution(mlmdtest.Execution{
+		Name:      "training",
+		Workspace: "workspace_1",
+		RunID:     run,
+		State:     pb.Execution_COMPLETE,
+		Inputs:    []int64{dataset},
+		Outputs:   []int64{model},
+	})
+	mlmd.SeedWorkspace("workspace_2")
+
+	return mlmd
+}
+
+func runCLI(t *testing.T, env map[string]string, args ...string) (string, string, int) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	c := cli{
+		stdout:       &stdout,
+		stderr:       &stderr,
+		getenv:       func(key string) string { return env[key] },
+		storeOptions: []registry.Option{registry.WithClient(exampleMLMD())},
+	}
+	code := c.run(args)
+	return stdout.String(), stderr.String(), code
+}
+
+func TestCommands(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"workspaces", "list"}, []string{"NAME", "workspace_1", "workspace_2"}},
+		{[]string{"artifacts", "list", "--workspace", "workspace_1"}, []string{"mnist", "MNIST", "DATASET", "gcs://my-bucket/mnist-data"}},
+		{[]string{"artifacts", "get", "2"}, []string{"MNIST", "1.0", "MODEL", "UNKNOWN"}},
+		{[]string{"lineage", "model", "2", "--workspace=workspace_1"}, []string{"mnist", "MNIST"}},
+		{[]string{"lineage", "run", "run-2021-03-30T16:50:45.608098", "--workspace", "workspace_1"}, []string{"mnist", "MNIST"}},
+	}
+	for _, test := range tests {
+		stdout, stderr, code := runCLI(t, nil, test.args...)
+		if code != exitOK {
+			t.Errorf("%v exited with %d: %s", test.args, code, stderr)
+			continue
+		}
+		for _, want := range test.want {
+			if !strings.Contains(stdout, want) {
+				t.Errorf("%v output does not contain %q:\n%s", test.args, want, stdout)
+			}
+		}
+	}
+
+	stdout, _, _ := runCLI(t, nil, "artifacts", "list", "--workspace", "workspace_1", "--type", "model")
+	if lines := strings.Split(strings.TrimSpace(stdout), "\n"); len(lines) != 2 || !strings.Contains(lines[1], "MNIST") {
+		t.Errorf("artifacts of type model:\n%s", stdout)
+	}
+}
+
+func TestOutputFormats(t *testing.T) {
+	stdout, stderr, code := runCLI(t, nil, "artifacts", "get", "2", "-o", "json")
+	if code != exitOK {
+		t.Fatalf("exited with %d: %s", code, stderr)
+	}
+	var response struct {
+		Artifacts []struct {
+			Id           string
+			Name         string
+			ArtifactType string
+		}
+	}
+	if err := json.Unmarshal([]byte(stdout), &response); err != nil {
+		t.Fatalf("invalid JSON %v:\n%s", err, stdout)
+	}
+	if len(response.Artifacts) != 1 || response.Artifacts[0].Id != "2" || response.Artifacts[0].ArtifactType != "MODEL" {
+		t.Errorf("JSON output = %+v", response)
+	}
+
+	stdout, stderr, code = runCLI(t, map[string]string{"ARTIFACT_REGISTRY_OUTPUT": "yaml"}, "artifacts", "get", "2")
+	if code != exitOK {
+		t.Fatalf("exited with %d: %s", code, stderr)
+	}
+	for _, want := range []string{"artifacts:\n  - artifactType: MODEL\n", "    id: \"2\"\n", "    uri: gcs://my-bucket/mnist\n", "      version: \"1.0\"\n", "    metadata:\n"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("YAML output does not contain %q:\n%s", want, stdout)
+		}
+	}
+
+	stdout, _, _ = runCLI(t, nil, "workspaces", "list", "--output", "yaml")
+	if !strings.HasPrefix(stdout, "workspaces:\n  - createTime: ") || !strings.Contains(stdout, "    name: workspace_2\n") {
+		t.Errorf("YAML workspaces:\n%s", stdout)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	tests := []struct {
+		args []string
+		code int
+	}{
+		{nil, exitUsage},
+		{[]string{"artifacts"}, exitUsage},
+		{[]string{"models", "list"}, exitUsage},
+		{[]string{"artifacts", "list"}, exitUsage},
+		{[]string{"artifacts", "get"}, exitUsage},
+		{[]string{"artifacts", "get", "2", "-o", "xml"}, exitUsage},
+		{[]string{"artifacts", "get", "--unknown", "2"}, exitUsage},
+		{[]string{"artifacts", "get", "two"}, exitError},
+		{[]string{"artifacts", "get", "42"}, exitError},
+		{[]string{"lineage", "model", "2", "--workspace", "missing"}, exitError},
+		{[]string{"-h"}, exitOK},
+	}
+	for _, test := range tests {
+		if _, _, code := runCLI(t, nil, test.args...); code != test.code {
+			t.Errorf("%v exited with %d, want %d", test.args, code, test.code)
+		}
+	}
+}
+
+func TestConnectionFlags(t *testing.T) {
+	env := map[string]string{"ARTIFACT_REGISTRY_HOST": "mlmd.internal", "ARTIFACT_REGISTRY_PORT": "9090", "ARTIFACT_REGISTRY_TLS": "true"}
+	c := cli{getenv: func(key string) string { return env[key] }}
+
+	var cfg config
+	flagSet := newFlagSet(t)
+	c.connectionFlags(flagSet, &cfg)
+	if _, err := parseInterspersed(flagSet, []string{"--port", "8443"}); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.host != "mlmd.internal" || cfg.port != "8443" || !cfg.tls || cfg.output != "table" {
+		t.Errorf("config = %+v", cfg)
+	}
+
+	cfg.caCert = "/does/not/exist.pem"
+	if _, err := c.artifactStore(cfg); err == nil {
+		t.Error("missing CA file did not fail")
+	}
+}
+
+func TestParseInterspersed(t *testing.T) {
+	var cfg config
+	flagSet := newFlagSet(t)
+	cli{getenv: func(string) string { return "" }}.workspaceFlag(flagSet, &cfg)
+
+	positional, err := parseInterspersed(flagSet, []string{"1", "--workspace", "w", "2", "--", "--3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(positional, ",") != "1,2,--3" || cfg.workspace != "w" {
+		t.Errorf("positional = %v, workspace = %s", positional, cfg.workspace)
+	}
+}
+
+func TestYAMLString(t *testing.T) {
+	tests := map[string]string{
+		"MNIST":                          "MNIST",
+		"gcs://my-bucket/mnist":          "gcs://my-bucket/mnist",
+		"":                               `""`,
+		"true":                           `"true"`,
+		"1.0":                            `"1.0"`,
+		"a: b":                           `"a: b"`,
+		"- item":                         `"- item"`,
+		"#comment":                       `"#comment"`,
+		"line\nbreak":                    `"line\nbreak"`,
+		"2021-03-30T16:50:45Z":           `"2021-03-30T16:50:45Z"`,
+		"2021-03-30":                     `"2021-03-30"`,
+		"16:50:45":                       `"16:50:45"`,
+		"run-2021-03-30T16:50:45.608098": `"run-2021-03-30T16:50:45.608098"`,
+	}
+	for value, want := range tests {
+		if got := yamlString(value); got != want {
+			t.Errorf("yamlString(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
+
+func newFlagSet(t *testing.T) *flag.FlagSet {
+	flagSet := flag.NewFlagSet(t.Name(), flag.ContinueOnError)
+	flagSet.SetOutput(ioutil.Discard)
+	return flagSet
+}
@@ -0,0 +1,242 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Table, JSON and YAML output of the CLI
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+	registry "github.com/Vernacular-ai/artifact-registry/registry"
+)
+
+type format int
+
+const (
+	formatTable format = iota
+	formatJSON
+	formatYAML
+)
+
+func parseFormat(name string) (format, error) {
+	switch strings.ToLower(name) {
+	case "table", "":
+		return formatTable, nil
+	case "json":
+		return formatJSON, nil
+	case "yaml", "yml":
+		return formatYAML, nil
+	}
+	return formatTable, fmt.Errorf("unknown output format %q, expected table, json or yaml", name)
+}
+
+// output is the result of a command.
+type output interface {
+	write(w io.Writer, format format) error
+}
+
+type artifactsOutput struct {
+	*pb.ArtifactsResponse
+}
+
+func (artifacts artifactsOutput) write(w io.Writer, format format) error {
+	if format != formatTable {
+		// Same encoding as the protobuf JSON mapping of gRPC gateways
+		encoded, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(artifacts.ArtifactsResponse)
+		if err != nil {
+			return err
+		}
+		return writeEncoded(w, format, encoded)
+	}
+
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tNAME\tVERSION\tTYPE\tSTATE\tURI\tRUN ID\tCREATED")
+	for _, artifact := range artifacts.GetArtifacts() {
+		fmt.Fprintf(table, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			artifact.GetId(), artifact.GetName(), artifact.GetVersion(), artifact.GetArtifactType(),
+			artifact.GetState(), artifact.GetUri(), artifact.GetRunId(), formatTimestamp(artifact.GetCreateTime()))
+	}
+	return table.Flush()
+}
+
+type workspacesOutput []registry.Workspace
+
+// workspaceJSON follows the JSON mapping of the protobuf messages
+type workspaceJSON struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Owner       string `json:"owner"`
+	CreateTime  string `json:"createTime,omitempty"`
+}
+
+func (workspaces workspacesOutput) write(w io.Writer, format format) error {
+	if format != formatTable {
+		var response struct {
+			Workspaces []workspaceJSON `json:"workspaces"`
+		}
+		response.Workspaces = []workspaceJSON{}
+		for _, workspace := range workspaces {
+			encoded := workspaceJSON{
+				Id:          strconv.FormatInt(workspace.Id, 10),
+				Name:        workspace.Name,
+				Description: workspace.Description,
+				Owner:       workspace.Owner,
+			}
+			if !workspace.CreateTime.IsZero() {
+				encoded.CreateTime = workspace.CreateTime.UTC().Format(time.RFC3339)
+			}
+			response.Workspaces = append(response.Workspaces, encoded)
+		}
+		encoded, err := json.Marshal(response)
+		if err != nil {
+			return err
+		}
+		return writeEncoded(w, format, encoded)
+	}
+
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "ID\tNAME\tOWNER\tDESCRIPTION\tCREATED")
+	for _, workspace := range workspaces {
+		created := ""
+		if !workspace.CreateTime.IsZero() {
+			created = workspace.CreateTime.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(table, "%d\t%s\t%s\t%s\t%s\n", workspace.Id, workspace.Name, workspace.Owner, workspace.Description, created)
+	}
+	return table.Flush()
+}
+
+func formatTimestamp(timestamp *timestamppb.Timestamp) string {
+	if timestamp == nil {
+		return ""
+	}
+	return timestamp.AsTime().UTC().Format(time.RFC3339)
+}
+
+// writeEncoded writes a JSON document indented, or converted to YAML.
+func writeEncoded(w io.Writer, format format, encoded []byte) error {
+	if format == formatJSON {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, encoded, "", "  "); err != nil {
+			return err
+		}
+		indented.WriteByte('\n')
+		_, err := indented.WriteTo(w)
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return err
+	}
+	var yaml bytes.Buffer
+	writeYAML(&yaml, document, 0)
+	_, err := yaml.WriteTo(w)
+	return err
+}
+
+// writeYAML writes a decoded JSON document as block style YAML, with sorted
+// keys.
+func writeYAML(w *bytes.Buffer, value interface{}, indent int) {
+	padding := strings.Repeat("  ", indent)
+	switch value := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			w.WriteString(padding + yamlString(key) + ":")
+			writeYAMLValue(w, value[key], indent)
+		}
+	case []interface{}:
+		for _, item := range value {
+			w.WriteString(padding + "-")
+			if nested, ok := item.(map[string]interface{}); ok && len(nested) > 0 {
+				// The first key goes on the line of the dash
+				var item bytes.Buffer
+				writeYAML(&item, nested, indent+1)
+				w.WriteString(" " + strings.TrimPrefix(item.String(), padding+"  "))
+				continue
+			}
+			writeYAMLValue(w, item, indent)
+		}
+	}
+}
+
+// writeYAMLValue writes the value of a key or list item, inline if it is a
+// scalar or an empty collection.
+func writeYAMLValue(w *bytes.Buffer, value interface{}, indent int) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if len(value) == 0 {
+			w.WriteString(" {}\n")
+			return
+		}
+		w.WriteString("\n")
+		writeYAML(w, value, indent+1)
+	case []interface{}:
+		if len(value) == 0 {
+			w.WriteString(" []\n")
+			return
+		}
+		w.WriteString("\n")
+		writeYAML(w, value, indent+1)
+	case string:
+		w.WriteString(" " + yamlString(value) + "\n")
+	case json.Number:
+		w.WriteString(" " + value.String() + "\n")
+	case bool:
+		w.WriteString(" " + strconv.FormatBool(value) + "\n")
+	case nil:
+		w.WriteString(" null\n")
+	}
+}
+
+var (
+	plainYAML    = regexp.MustCompile(`^[A-Za-z0-9_./][A-Za-z0-9_./:@+ -]*$`)
+	reservedYAML = regexp.MustCompile(`^(?i:true|false|yes|no|on|off|y|n|null|~|[-+]?[0-9][0-9_]*(\.[0-9_]*)?([eE][-+]?[0-9]+)?|[-+]?[0-9][0-9_]*(:[0-5]?[0-9])+(\.[0-9_]*)?|[-+]?\.(inf|nan)|0x[0-9a-f]+|0o[0-7]+)$`)
+	// Dates and times, which some parsers read as timestamps even within a
+	// longer string, e.g. the run ID run-2021-03-30T16:50:45.608098
+	timestampYAML = regexp.MustCompile(`[0-9]{4}-[0-9]{1,2}-[0-9]{1,2}|[0-9]{1,2}:[0-9]{2}:[0-9]{2}`)
+)
+
+// yamlString writes a string plain when YAML reads it back as the same
+// string, double quoted otherwise. JSON strings are valid YAML double quoted
+// strings.
+func yamlString(value string) string {
+	if plainYAML.MatchString(value) && !reservedYAML.MatchString(value) && !timestampYAML.MatchString(value) &&
+		!strings.Contains(value, ": ") && !strings.HasSuffix(value, ":") && !strings.HasSuffix(value, " ") {
+		return value
+	}
+	quoted, _ := json.Marshal(value)
+	return string(quoted)
+}
@@ -7,9 +7,14 @@
 package artifact_registry
 
 import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -73,6 +78,62 @@ func (ArtifactData_ArtifactType) EnumDescriptor() ([]byte, []int) {
 	return file_artifact_registry_proto_rawDescGZIP(), []int{1, 0}
 }
 
+// Same as the state of an MLMD artifact
+type ArtifactData_State int32
+
+const (
+	ArtifactData_UNKNOWN             ArtifactData_State = 0
+	ArtifactData_PENDING             ArtifactData_State = 1
+	ArtifactData_LIVE                ArtifactData_State = 2
+	ArtifactData_MARKED_FOR_DELETION ArtifactData_State = 3
+	ArtifactData_DELETED             ArtifactData_State = 4
+)
+
+// Enum value maps for ArtifactData_State.
+var (
+	ArtifactData_State_name = map[int32]string{
+		0: "UNKNOWN",
+		1: "PENDING",
+		2: "LIVE",
+		3: "MARKED_FOR_DELETION",
+		4: "DELETED",
+	}
+	ArtifactData_State_value = map[string]int32{
+		"UNKNOWN":             0,
+		"PENDING":             1,
+		"LIVE":                2,
+		"MARKED_FOR_DELETION": 3,
+		"DELETED":             4,
+	}
+)
+
+func (x ArtifactData_State) Enum() *ArtifactData_State {
+	p := new(ArtifactData_State)
+	*p = x
+	return p
+}
+
+func (x ArtifactData_State) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ArtifactData_State) Descriptor() protoreflect.EnumDescriptor {
+	return file_artifact_registry_proto_enumTypes[1].Descriptor()
+}
+
+func (ArtifactData_State) Type() protoreflect.EnumType {
+	return &file_artifact_registry_proto_enumTypes[1]
+}
+
+func (x ArtifactData_State) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ArtifactData_State.Descriptor instead.
+func (ArtifactData_State) EnumDescriptor() ([]byte, []int) {
+	return file_artifact_registry_proto_rawDescGZIP(), []int{1, 1}
+}
+
 type ArtifactByTypeRequest_ArtifactType int32
 
 const (
@@ -106,11 +167,11 @@ func (x ArtifactByTypeRequest_ArtifactType) String() string {
 }
 
 func (ArtifactByTypeRequest_ArtifactType) Descriptor() protoreflect.EnumDescriptor {
-	return file_artifact_registry_proto_enumTypes[1].Descriptor()
+	return file_artifact_registry_proto_enumTypes[2].Descriptor()
 }
 
 func (ArtifactByTypeRequest_ArtifactType) Type() protoreflect.EnumType {
-	return &file_artifact_registry_proto_enumTypes[1]
+	return &file_artifact_registry_proto_enumTypes[2]
 }
 
 func (x ArtifactByTypeRequest_ArtifactType) Number() protoreflect.EnumNumber {
@@ -180,7 +241,13 @@ type ArtifactData struct {
 	RunId        string                    `protobuf:"bytes,4,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
 	Id           int64                     `protobuf:"varint,5,opt,name=id,proto3" json:"id,omitempty"`
 	ArtifactType ArtifactData_ArtifactType `protobuf:"varint,6,opt,name=artifact_type,json=artifactType,proto3,enum=artifact_registry.ArtifactData_ArtifactType" json:"artifact_type,omitempty"`
-	Metadata     *structpb.Struct          `protobuf:"bytes,7,opt,name=metadata,proto3,oneof" json:"metadata,omitempty"`
+	// Properties and custom properties of the artifact
+	Metadata       *structpb.Struct       `protobuf:"bytes,7,opt,name=metadata,proto3,oneof" json:"metadata,omitempty"`
+	State          ArtifactData_State     `protobuf:"varint,8,opt,name=state,proto3,enum=artifact_registry.ArtifactData_State" json:"state,omitempty"`
+	CreateTime     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	LastUpdateTime *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=last_update_time,json=lastUpdateTime,proto3" json:"last_update_time,omitempty"`
+	// Name of the MLMD artifact type, e.g. system.Model
+	TypeName string `protobuf:"bytes,11,opt,name=type_name,json=typeName,proto3" json:"type_name,omitempty"`
 }
 
 func (x *ArtifactData) Reset() {
@@ -264,12 +331,42 @@ func (x *ArtifactData) GetMetadata() *structpb.Struct {
 	return nil
 }
 
+func (x *ArtifactData) GetState() ArtifactData_State {
+	if x != nil {
+		return x.State
+	}
+	return ArtifactData_UNKNOWN
+}
+
+func (x *ArtifactData) GetCreateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreateTime
+	}
+	return nil
+}
+
+func (x *ArtifactData) GetLastUpdateTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastUpdateTime
+	}
+	return nil
+}
+
+func (x *ArtifactData) GetTypeName() string {
+	if x != nil {
+		return x.TypeName
+	}
+	return ""
+}
+
 type ArtifactByTypeRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	ArtifactType ArtifactByTypeRequest_ArtifactType `protobuf:"varint,1,opt,name=artifact_type,json=artifactType,proto3,enum=artifact_registry.ArtifactByTypeRequest_ArtifactType" json:"artifact_type,omitempty"`
+	// Name of the MLMD artifact type to fetch instead of artifact_type
+	TypeName string `protobuf:"bytes,2,opt,name=type_name,json=typeName,proto3" json:"type_name,omitempty"`
 }
 
 func (x *ArtifactByTypeRequest) Reset() {
@@ -311,6 +408,13 @@ func (x *ArtifactByTypeRequest) GetArtifactType() ArtifactByTypeRequest_Artifact
 	return ArtifactByTypeRequest_MODEL
 }
 
+func (x *ArtifactByTypeRequest) GetTypeName() string {
+	if x != nil {
+		return x.TypeName
+	}
+	return ""
+}
+
 type ArtifactsByRunRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -499,6 +603,171 @@ func (x *Workspace) GetName() string {
 	return ""
 }
 
+type GetArtifactsByTypeWorkspaceRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Workspace           *Workspace             `protobuf:"bytes,1,opt,name=workspace,proto3" json:"workspace,omitempty"`
+	ArtifactTypeRequest *ArtifactByTypeRequest `protobuf:"bytes,2,opt,name=artifact_type_request,json=artifactTypeRequest,proto3" json:"artifact_type_request,omitempty"`
+}
+
+func (x *GetArtifactsByTypeWorkspaceRequest) Reset() {
+	*x = GetArtifactsByTypeWorkspaceRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_artifact_registry_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetArtifactsByTypeWorkspaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetArtifactsByTypeWorkspaceRequest) ProtoMessage() {}
+
+func (x *GetArtifactsByTypeWorkspaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_artifact_registry_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetArtifactsByTypeWorkspaceRequest.ProtoReflect.Descriptor instead.
+func (*GetArtifactsByTypeWorkspaceRequest) Descriptor() ([]byte, []int) {
+	return file_artifact_registry_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetArtifactsByTypeWorkspaceRequest) GetWorkspace() *Workspace {
+	if x != nil {
+		return x.Workspace
+	}
+	return nil
+}
+
+func (x *GetArtifactsByTypeWorkspaceRequest) GetArtifactTypeRequest() *ArtifactByTypeRequest {
+	if x != nil {
+		return x.ArtifactTypeRequest
+	}
+	return nil
+}
+
+type GetLineageByRunRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Workspace             *Workspace             `protobuf:"bytes,1,opt,name=workspace,proto3" json:"workspace,omitempty"`
+	ArtifactsByRunRequest *ArtifactsByRunRequest `protobuf:"bytes,2,opt,name=artifacts_by_run_request,json=artifactsByRunRequest,proto3" json:"artifacts_by_run_request,omitempty"`
+}
+
+func (x *GetLineageByRunRequest) Reset() {
+	*x = GetLineageByRunRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_artifact_registry_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetLineageByRunRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLineageByRunRequest) ProtoMessage() {}
+
+func (x *GetLineageByRunRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_artifact_registry_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLineageByRunRequest.ProtoReflect.Descriptor instead.
+func (*GetLineageByRunRequest) Descriptor() ([]byte, []int) {
+	return file_artifact_registry_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetLineageByRunRequest) GetWorkspace() *Workspace {
+	if x != nil {
+		return x.Workspace
+	}
+	return nil
+}
+
+func (x *GetLineageByRunRequest) GetArtifactsByRunRequest() *ArtifactsByRunRequest {
+	if x != nil {
+		return x.ArtifactsByRunRequest
+	}
+	return nil
+}
+
+type GetLineageByModelRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Workspace               *Workspace               `protobuf:"bytes,1,opt,name=workspace,proto3" json:"workspace,omitempty"`
+	ArtifactsByModelRequest *ArtifactsByModelRequest `protobuf:"bytes,2,opt,name=artifacts_by_model_request,json=artifactsByModelRequest,proto3" json:"artifacts_by_model_request,omitempty"`
+}
+
+func (x *GetLineageByModelRequest) Reset() {
+	*x = GetLineageByModelRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_artifact_registry_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetLineageByModelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLineageByModelRequest) ProtoMessage() {}
+
+func (x *GetLineageByModelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_artifact_registry_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLineageByModelRequest.ProtoReflect.Descriptor instead.
+func (*GetLineageByModelRequest) Descriptor() ([]byte, []int) {
+	return file_artifact_registry_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetLineageByModelRequest) GetWorkspace() *Workspace {
+	if x != nil {
+		return x.Workspace
+	}
+	return nil
+}
+
+func (x *GetLineageByModelRequest) GetArtifactsByModelRequest() *ArtifactsByModelRequest {
+	if x != nil {
+		return x.ArtifactsByModelRequest
+	}
+	return nil
+}
+
 var File_artifact_registry_proto protoreflect.FileDescriptor
 
 var file_artifact_registry_proto_rawDesc = []byte{
@@ -506,57 +775,154 @@ var file_artifact_registry_proto_rawDesc = []byte{
 	0x74, 0x72, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x11, 0x61, 0x72, 0x74, 0x69, 0x66,
 	0x61, 0x63, 0x74, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x1a, 0x1c, 0x67, 0x6f,
 	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x74,
-	0x72, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x1e, 0x0a, 0x0a, 0x4d, 0x4c,
-	0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x69, 0x64, 0x73, 0x18,
-	0x01, 0x20, 0x03, 0x28, 0x03, 0x52, 0x03, 0x69, 0x64, 0x73, 0x22, 0xcf, 0x02, 0x0a, 0x0c, 0x41,
-	0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e,
-	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
-	0x10, 0x0a, 0x03, 0x75, 0x72, 0x69, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72,
-	0x69, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x15, 0x0a, 0x06, 0x72,
-	0x75, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x72, 0x75, 0x6e,
-	0x49, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02,
-	0x69, 0x64, 0x12, 0x51, 0x0a, 0x0d, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f, 0x74,
-	0x79, 0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x2c, 0x2e, 0x61, 0x72, 0x74, 0x69,
-	0x66, 0x61, 0x63, 0x74, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x41, 0x72,
-	0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x44, 0x61, 0x74, 0x61, 0x2e, 0x41, 0x72, 0x74, 0x69, 0x66,
-	0x61, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0c, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63,
-	0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x38, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
-	0x61, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74,
-	0x48, 0x00, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x88, 0x01, 0x01, 0x22,
-	0x3e, 0x0a, 0x0c, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12,
-	0x09, 0x0a, 0x05, 0x4d, 0x4f, 0x44, 0x45, 0x4c, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x4d, 0x45,
-	0x54, 0x52, 0x49, 0x43, 0x53, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x41, 0x54, 0x41, 0x53,
-	0x45, 0x54, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x4f, 0x54, 0x48, 0x45, 0x52, 0x10, 0x03, 0x42,
-	0x0b, 0x0a, 0x09, 0x5f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x22, 0xa8, 0x01, 0x0a,
-	0x15, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x42, 0x79, 0x54, 0x79, 0x70, 0x65, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x5a, 0x0a, 0x0d, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61,
-	0x63, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x35, 0x2e,
+	0x72, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x1e, 0x0a, 0x0a, 0x4d,
+	0x4c, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x69, 0x64, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x03, 0x52, 0x03, 0x69, 0x64, 0x73, 0x22, 0xff, 0x04, 0x0a, 0x0c,
+	0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x69, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75,
+	0x72, 0x69, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x15, 0x0a, 0x06,
+	0x72, 0x75, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x72, 0x75,
+	0x6e, 0x49, 0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x02, 0x69, 0x64, 0x12, 0x51, 0x0a, 0x0d, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x2c, 0x2e, 0x61, 0x72, 0x74,
+	0x69, 0x66, 0x61, 0x63, 0x74, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x41,
+	0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x44, 0x61, 0x74, 0x61, 0x2e, 0x41, 0x72, 0x74, 0x69,
+	0x66, 0x61, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0c, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61,
+	0x63, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x38, 0x0a, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63,
+	0x74, 0x48, 0x00, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x88, 0x01, 0x01,
+	0x12, 0x3b, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x25, 0x2e, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x72, 0x79, 0x2e, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x44, 0x61, 0x74, 0x61,
+	0x2e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x3b, 0x0a,
+	0x0b, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x44, 0x0a, 0x10, 0x6c, 0x61,
+	0x73, 0x74, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x0e, 0x6c, 0x61, 0x73, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x69, 0x6d, 0x65,
+	0x12, 0x1b, 0x0a, 0x09, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x79, 0x70, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x3e, 0x0a,
+	0x0c, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x09, 0x0a,
+	0x05, 0x4d, 0x4f, 0x44, 0x45, 0x4c, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x4d, 0x45, 0x54, 0x52,
+	0x49, 0x43, 0x53, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x41, 0x54, 0x41, 0x53, 0x45, 0x54,
+	0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x4f, 0x54, 0x48, 0x45, 0x52, 0x10, 0x03, 0x22, 0x51, 0x0a,
+	0x05, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57,
+	0x4e, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x10, 0x01,
+	0x12, 0x08, 0x0a, 0x04, 0x4c, 0x49, 0x56, 0x45, 0x10, 0x02, 0x12, 0x17, 0x0a, 0x13, 0x4d, 0x41,
+	0x52, 0x4b, 0x45, 0x44, 0x5f, 0x46, 0x4f, 0x52, 0x5f, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x49, 0x4f,
+	0x4e, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x04,
+	0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x22, 0xc5, 0x01,
+	0x0a, 0x15, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x42, 0x79, 0x54, 0x79, 0x70, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x5a, 0x0a, 0x0d, 0x61, 0x72, 0x74, 0x69, 0x66,
+	0x61, 0x63, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x35,
+	0x2e, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x72, 0x79, 0x2e, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x42, 0x79, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63,
+	0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0c, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x79, 0x70, 0x65, 0x4e, 0x61, 0x6d, 0x65,
+	0x22, 0x33, 0x0a, 0x0c, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x09, 0x0a, 0x05, 0x4d, 0x4f, 0x44, 0x45, 0x4c, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x4d,
+	0x45, 0x54, 0x52, 0x49, 0x43, 0x53, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x41, 0x54, 0x41,
+	0x53, 0x45, 0x54, 0x10, 0x02, 0x22, 0x2e, 0x0a, 0x15, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63,
+	0x74, 0x73, 0x42, 0x79, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15,
+	0x0a, 0x06, 0x72, 0x75, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x72, 0x75, 0x6e, 0x49, 0x64, 0x22, 0x34, 0x0a, 0x17, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63,
+	0x74, 0x73, 0x42, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x19, 0x0a, 0x08, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x07, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x49, 0x64, 0x22, 0x52, 0x0a, 0x11, 0x41,
+	0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x3d, 0x0a, 0x09, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f, 0x72,
+	0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74,
+	0x44, 0x61, 0x74, 0x61, 0x52, 0x09, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x22,
+	0x1f, 0x0a, 0x09, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x22, 0xbe, 0x01, 0x0a, 0x22, 0x47, 0x65, 0x74, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74,
+	0x73, 0x42, 0x79, 0x54, 0x79, 0x70, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3a, 0x0a, 0x09, 0x77, 0x6f, 0x72, 0x6b, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x61, 0x72, 0x74,
+	0x69, 0x66, 0x61, 0x63, 0x74, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x57,
+	0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x09, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x12, 0x5c, 0x0a, 0x15, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f,
+	0x74, 0x79, 0x70, 0x65, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x28, 0x2e, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f, 0x72, 0x65,
+	0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x42,
+	0x79, 0x54, 0x79, 0x70, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x13, 0x61, 0x72,
+	0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x22, 0xb7, 0x01, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x61, 0x67, 0x65,
+	0x42, 0x79, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3a, 0x0a, 0x09,
+	0x77, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1c, 0x2e, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x72, 0x79, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x09, 0x77,
+	0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x61, 0x0a, 0x18, 0x61, 0x72, 0x74, 0x69,
+	0x66, 0x61, 0x63, 0x74, 0x73, 0x5f, 0x62, 0x79, 0x5f, 0x72, 0x75, 0x6e, 0x5f, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x61, 0x72, 0x74,
+	0x69, 0x66, 0x61, 0x63, 0x74, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x41,
+	0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x42, 0x79, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x52, 0x15, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x42,
+	0x79, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xbf, 0x01, 0x0a, 0x18,
+	0x47, 0x65, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x61, 0x67, 0x65, 0x42, 0x79, 0x4d, 0x6f, 0x64, 0x65,
+	0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3a, 0x0a, 0x09, 0x77, 0x6f, 0x72, 0x6b,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x61, 0x72,
+	0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x2e,
+	0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x09, 0x77, 0x6f, 0x72, 0x6b, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x12, 0x67, 0x0a, 0x1a, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74,
+	0x73, 0x5f, 0x62, 0x79, 0x5f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x61, 0x72, 0x74, 0x69, 0x66,
+	0x61, 0x63, 0x74, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x41, 0x72, 0x74,
+	0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x42, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x52, 0x17, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x42,
+	0x79, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x32, 0xf1, 0x04,
+	0x0a, 0x17, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74,
+	0x72, 0x79, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x59, 0x0a, 0x10, 0x47, 0x65, 0x74,
+	0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x42, 0x79, 0x49, 0x44, 0x12, 0x1d, 0x2e,
+	0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72,
+	0x79, 0x2e, 0x4d, 0x4c, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x1a, 0x24, 0x2e, 0x61,
+	0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79,
+	0x2e, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x00, 0x12, 0x4c, 0x0a, 0x0c, 0x47, 0x65, 0x74, 0x57, 0x6f, 0x72, 0x6b, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x12, 0x1c, 0x2e, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f,
+	0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x1a, 0x1c, 0x2e, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f, 0x72, 0x65,
+	0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x22, 0x00, 0x12, 0x5f, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63,
+	0x74, 0x73, 0x42, 0x79, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x1c, 0x2e,
 	0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72,
-	0x79, 0x2e, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x42, 0x79, 0x54, 0x79, 0x70, 0x65,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74,
-	0x54, 0x79, 0x70, 0x65, 0x52, 0x0c, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x54, 0x79,
-	0x70, 0x65, 0x22, 0x33, 0x0a, 0x0c, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x54, 0x79,
-	0x70, 0x65, 0x12, 0x09, 0x0a, 0x05, 0x4d, 0x4f, 0x44, 0x45, 0x4c, 0x10, 0x00, 0x12, 0x0b, 0x0a,
-	0x07, 0x4d, 0x45, 0x54, 0x52, 0x49, 0x43, 0x53, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x41,
-	0x54, 0x41, 0x53, 0x45, 0x54, 0x10, 0x02, 0x22, 0x2e, 0x0a, 0x15, 0x41, 0x72, 0x74, 0x69, 0x66,
-	0x61, 0x63, 0x74, 0x73, 0x42, 0x79, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x15, 0x0a, 0x06, 0x72, 0x75, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x05, 0x72, 0x75, 0x6e, 0x49, 0x64, 0x22, 0x34, 0x0a, 0x17, 0x41, 0x72, 0x74, 0x69, 0x66,
-	0x61, 0x63, 0x74, 0x73, 0x42, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x49, 0x64, 0x22, 0x52, 0x0a,
-	0x11, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x3d, 0x0a, 0x09, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x18,
-	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74,
-	0x5f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61,
-	0x63, 0x74, 0x44, 0x61, 0x74, 0x61, 0x52, 0x09, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74,
-	0x73, 0x22, 0x1f, 0x0a, 0x09, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x12,
-	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x42, 0x15, 0x5a, 0x13, 0x2f, 0x3b, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74,
-	0x5f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x79, 0x2e, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61, 0x63, 0x65, 0x1a, 0x24, 0x2e, 0x61, 0x72,
+	0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x2e,
+	0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x22, 0x00, 0x12, 0x7c, 0x0a, 0x1b, 0x47, 0x65, 0x74, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61,
+	0x63, 0x74, 0x73, 0x42, 0x79, 0x54, 0x79, 0x70, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x12, 0x35, 0x2e, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f, 0x72, 0x65,
+	0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x47, 0x65, 0x74, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61,
+	0x63, 0x74, 0x73, 0x42, 0x79, 0x54, 0x79, 0x70, 0x65, 0x57, 0x6f, 0x72, 0x6b, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x61, 0x72, 0x74, 0x69,
+	0x66, 0x61, 0x63, 0x74, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x41, 0x72,
+	0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x64, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x61, 0x67, 0x65, 0x42,
+	0x79, 0x52, 0x75, 0x6e, 0x12, 0x29, 0x2e, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f,
+	0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x47, 0x65, 0x74, 0x4c, 0x69, 0x6e, 0x65,
+	0x61, 0x67, 0x65, 0x42, 0x79, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x24, 0x2e, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x73,
+	0x74, 0x72, 0x79, 0x2e, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x68, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x4c, 0x69,
+	0x6e, 0x65, 0x61, 0x67, 0x65, 0x42, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x2b, 0x2e, 0x61,
+	0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79,
+	0x2e, 0x47, 0x65, 0x74, 0x4c, 0x69, 0x6e, 0x65, 0x61, 0x67, 0x65, 0x42, 0x79, 0x4d, 0x6f, 0x64,
+	0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x61, 0x72, 0x74, 0x69,
+	0x66, 0x61, 0x63, 0x74, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x2e, 0x41, 0x72,
+	0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x42, 0x15, 0x5a, 0x13, 0x2f, 0x3b, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x5f,
+	0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x79, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -571,30 +937,56 @@ func file_artifact_registry_proto_rawDescGZIP() []byte {
 	return file_artifact_registry_proto_rawDescData
 }
 
-var file_artifact_registry_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_artifact_registry_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_artifact_registry_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_artifact_registry_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_artifact_registry_proto_goTypes = []interface{}{
-	(ArtifactData_ArtifactType)(0),          // 0: artifact_registry.ArtifactData.ArtifactType
-	(ArtifactByTypeRequest_ArtifactType)(0), // 1: artifact_registry.ArtifactByTypeRequest.ArtifactType
-	(*MLArtifact)(nil),                      // 2: artifact_registry.MLArtifact
-	(*ArtifactData)(nil),                    // 3: artifact_registry.ArtifactData
-	(*ArtifactByTypeRequest)(nil),           // 4: artifact_registry.ArtifactByTypeRequest
-	(*ArtifactsByRunRequest)(nil),           // 5: artifact_registry.ArtifactsByRunRequest
-	(*ArtifactsByModelRequest)(nil),         // 6: artifact_registry.ArtifactsByModelRequest
-	(*ArtifactsResponse)(nil),               // 7: artifact_registry.ArtifactsResponse
-	(*Workspace)(nil),                       // 8: artifact_registry.Workspace
-	(*structpb.Struct)(nil),                 // 9: google.protobuf.Struct
+	(ArtifactData_ArtifactType)(0),             // 0: artifact_registry.ArtifactData.ArtifactType
+	(ArtifactData_State)(0),                    // 1: artifact_registry.ArtifactData.State
+	(ArtifactByTypeRequest_ArtifactType)(0),    // 2: artifact_registry.ArtifactByTypeRequest.ArtifactType
+	(*MLArtifact)(nil),                         // 3: artifact_registry.MLArtifact
+	(*ArtifactData)(nil),                       // 4: artifact_registry.ArtifactData
+	(*ArtifactByTypeRequest)(nil),              // 5: artifact_registry.ArtifactByTypeRequest
+	(*ArtifactsByRunRequest)(nil),              // 6: artifact_registry.ArtifactsByRunRequest
+	(*ArtifactsByModelRequest)(nil),            // 7: artifact_registry.ArtifactsByModelRequest
+	(*ArtifactsResponse)(nil),                  // 8: artifact_registry.ArtifactsResponse
+	(*Workspace)(nil),                          // 9: artifact_registry.Workspace
+	(*GetArtifactsByTypeWorkspaceRequest)(nil), // 10: artifact_registry.GetArtifactsByTypeWorkspaceRequest
+	(*GetLineageByRunRequest)(nil),             // 11: artifact_registry.GetLineageByRunRequest
+	(*GetLineageByModelRequest)(nil),           // 12: artifact_registry.GetLineageByModelRequest
+	(*structpb.Struct)(nil),                    // 13: google.protobuf.Struct
+	(*timestamppb.Timestamp)(nil),              // 14: google.protobuf.Timestamp
 }
 var file_artifact_registry_proto_depIdxs = []int32{
-	0, // 0: artifact_registry.ArtifactData.artifact_type:type_name -> artifact_registry.ArtifactData.ArtifactType
-	9, // 1: artifact_registry.ArtifactData.metadata:type_name -> google.protobuf.Struct
-	1, // 2: artifact_registry.ArtifactByTypeRequest.artifact_type:type_name -> artifact_registry.ArtifactByTypeRequest.ArtifactType
-	3, // 3: artifact_registry.ArtifactsResponse.artifacts:type_name -> artifact_registry.ArtifactData
-	4, // [4:4] is the sub-list for method output_type
-	4, // [4:4] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	0,  // 0: artifact_registry.ArtifactData.artifact_type:type_name -> artifact_registry.ArtifactData.ArtifactType
+	13, // 1: artifact_registry.ArtifactData.metadata:type_name -> google.protobuf.Struct
+	1,  // 2: artifact_registry.ArtifactData.state:type_name -> artifact_registry.ArtifactData.State
+	14, // 3: artifact_registry.ArtifactData.create_time:type_name -> google.protobuf.Timestamp
+	14, // 4: artifact_registry.ArtifactData.last_update_time:type_name -> google.protobuf.Timestamp
+	2,  // 5: artifact_registry.ArtifactByTypeRequest.artifact_type:type_name -> artifact_registry.ArtifactByTypeRequest.ArtifactType
+	4,  // 6: artifact_registry.ArtifactsResponse.artifacts:type_name -> artifact_registry.ArtifactData
+	9,  // 7: artifact_registry.GetArtifactsByTypeWorkspaceRequest.workspace:type_name -> artifact_registry.Workspace
+	5,  // 8: artifact_registry.GetArtifactsByTypeWorkspaceRequest.artifact_type_request:type_name -> artifact_registry.ArtifactByTypeRequest
+	9,  // 9: artifact_registry.GetLineageByRunRequest.workspace:type_name -> artifact_registry.Workspace
+	6,  // 10: artifact_registry.GetLineageByRunRequest.artifacts_by_run_request:type_name -> artifact_registry.ArtifactsByRunRequest
+	9,  // 11: artifact_registry.GetLineageByModelRequest.workspace:type_name -> artifact_registry.Workspace
+	7,  // 12: artifact_registry.GetLineageByModelRequest.artifacts_by_model_request:type_name -> artifact_registry.ArtifactsByModelRequest
+	3,  // 13: artifact_registry.ArtifactRegistryService.GetArtifactsByID:input_type -> artifact_registry.MLArtifact
+	9,  // 14: artifact_registry.ArtifactRegistryService.GetWorkspace:input_type -> artifact_registry.Workspace
+	9,  // 15: artifact_registry.ArtifactRegistryService.GetArtifactsByWorkspace:input_type -> artifact_registry.Workspace
+	10, // 16: artifact_registry.ArtifactRegistryService.GetArtifactsByTypeWorkspace:input_type -> artifact_registry.GetArtifactsByTypeWorkspaceRequest
+	11, // 17: artifact_registry.ArtifactRegistryService.GetLineageByRun:input_type -> artifact_registry.GetLineageByRunRequest
+	12, // 18: artifact_registry.ArtifactRegistryService.GetLineageByModel:input_type -> artifact_registry.GetLineageByModelRequest
+	8,  // 19: artifact_registry.ArtifactRegistryService.GetArtifactsByID:output_type -> artifact_registry.ArtifactsResponse
+	9,  // 20: artifact_registry.ArtifactRegistryService.GetWorkspace:output_type -> artifact_registry.Workspace
+	8,  // 21: artifact_registry.ArtifactRegistryService.GetArtifactsByWorkspace:output_type -> artifact_registry.ArtifactsResponse
+	8,  // 22: artifact_registry.ArtifactRegistryService.GetArtifactsByTypeWorkspace:output_type -> artifact_registry.ArtifactsResponse
+	8,  // 23: artifact_registry.ArtifactRegistryService.GetLineageByRun:output_type -> artifact_registry.ArtifactsResponse
+	8,  // 24: artifact_registry.ArtifactRegistryService.GetLineageByModel:output_type -> artifact_registry.ArtifactsResponse
+	19, // [19:25] is the sub-list for method output_type
+	13, // [13:19] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_artifact_registry_proto_init() }
@@ -687,6 +1079,42 @@ func file_artifact_registry_proto_init() {
 				return nil
 			}
 		}
+		file_artifact_registry_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetArtifactsByTypeWorkspaceRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_artifact_registry_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetLineageByRunRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_artifact_registry_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetLineageByModelRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_artifact_registry_proto_msgTypes[1].OneofWrappers = []interface{}{}
 	type x struct{}
@@ -694,10 +1122,10 @@ func file_artifact_registry_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_artifact_registry_proto_rawDesc,
-			NumEnums:      2,
-			NumMessages:   7,
+			NumEnums:      3,
+			NumMessages:   10,
 			NumExtensions: 0,
-			NumServices:   0,
+			NumServices:   1,
 		},
 		GoTypes:           file_artifact_registry_proto_goTypes,
 		DependencyIndexes: file_artifact_registry_proto_depIdxs,
@@ -709,3 +1137,275 @@ func file_artifact_registry_proto_init() {
 	file_artifact_registry_proto_goTypes = nil
 	file_artifact_registry_proto_depIdxs = nil
 }
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// ArtifactRegistryServiceClient is the client API for ArtifactRegistryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ArtifactRegistryServiceClient interface {
+	// Artifacts by ID, in any workspace
+	GetArtifactsByID(ctx context.Context, in *MLArtifact, opts ...grpc.CallOption) (*ArtifactsResponse, error)
+	// The workspace of the given name
+	GetWorkspace(ctx context.Context, in *Workspace, opts ...grpc.CallOption) (*Workspace, error)
+	// Artifacts of a workspace
+	GetArtifactsByWorkspace(ctx context.Context, in *Workspace, opts ...grpc.CallOption) (*ArtifactsResponse, error)
+	// Artifacts of a workspace having a certain type
+	GetArtifactsByTypeWorkspace(ctx context.Context, in *GetArtifactsByTypeWorkspaceRequest, opts ...grpc.CallOption) (*ArtifactsResponse, error)
+	// Artifacts of a workspace associated with a Kubeflow run
+	GetLineageByRun(ctx context.Context, in *GetLineageByRunRequest, opts ...grpc.CallOption) (*ArtifactsResponse, error)
+	// Artifacts of a workspace related to a model through its executions
+	GetLineageByModel(ctx context.Context, in *GetLineageByModelRequest, opts ...grpc.CallOption) (*ArtifactsResponse, error)
+}
+
+type artifactRegistryServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewArtifactRegistryServiceClient(cc grpc.ClientConnInterface) ArtifactRegistryServiceClient {
+	return &artifactRegistryServiceClient{cc}
+}
+
+func (c *artifactRegistryServiceClient) GetArtifactsByID(ctx context.Context, in *MLArtifact, opts ...grpc.CallOption) (*ArtifactsResponse, error) {
+	out := new(ArtifactsResponse)
+	err := c.cc.Invoke(ctx, "/artifact_registry.ArtifactRegistryService/GetArtifactsByID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *artifactRegistryServiceClient) GetWorkspace(ctx context.Context, in *Workspace, opts ...grpc.CallOption) (*Workspace, error) {
+	out := new(Workspace)
+	err := c.cc.Invoke(ctx, "/artifact_registry.ArtifactRegistryService/GetWorkspace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *artifactRegistryServiceClient) GetArtifactsByWorkspace(ctx context.Context, in *Workspace, opts ...grpc.CallOption) (*ArtifactsResponse, error) {
+	out := new(ArtifactsResponse)
+	err := c.cc.Invoke(ctx, "/artifact_registry.ArtifactRegistryService/GetArtifactsByWorkspace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *artifactRegistryServiceClient) GetArtifactsByTypeWorkspace(ctx context.Context, in *GetArtifactsByTypeWorkspaceRequest, opts ...grpc.CallOption) (*ArtifactsResponse, error) {
+	out := new(ArtifactsResponse)
+	err := c.cc.Invoke(ctx, "/artifact_registry.ArtifactRegistryService/GetArtifactsByTypeWorkspace", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *artifactRegistryServiceClient) GetLineageByRun(ctx context.Context, in *GetLineageByRunRequest, opts ...grpc.CallOption) (*ArtifactsResponse, error) {
+	out := new(ArtifactsResponse)
+	err := c.cc.Invoke(ctx, "/artifact_registry.ArtifactRegistryService/GetLineageByRun", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *artifactRegistryServiceClient) GetLineageByModel(ctx context.Context, in *GetLineageByModelRequest, opts ...grpc.CallOption) (*ArtifactsResponse, error) {
+	out := new(ArtifactsResponse)
+	err := c.cc.Invoke(ctx, "/artifact_registry.ArtifactRegistryService/GetLineageByModel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ArtifactRegistryServiceServer is the server API for ArtifactRegistryService service.
+type ArtifactRegistryServiceServer interface {
+	// Artifacts by ID, in any workspace
+	GetArtifactsByID(context.Context, *MLArtifact) (*ArtifactsResponse, error)
+	// The workspace of the given name
+	GetWorkspace(context.Context, *Workspace) (*Workspace, error)
+	// Artifacts of a workspace
+	GetArtifactsByWorkspace(context.Context, *Workspace) (*ArtifactsResponse, error)
+	// Artifacts of a workspace having a certain type
+	GetArtifactsByTypeWorkspace(context.Context, *GetArtifactsByTypeWorkspaceRequest) (*ArtifactsResponse, error)
+	// Artifacts of a workspace associated with a Kubeflow run
+	GetLineageByRun(context.Context, *GetLineageByRunRequest) (*ArtifactsResponse, error)
+	// Artifacts of a workspace related to a model through its executions
+	GetLineageByModel(context.Context, *GetLineageByModelRequest) (*ArtifactsResponse, error)
+}
+
+// UnimplementedArtifactRegistryServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedArtifactRegistryServiceServer struct {
+}
+
+func (*UnimplementedArtifactRegistryServiceServer) GetArtifactsByID(context.Context, *MLArtifact) (*ArtifactsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetArtifactsByID not implemented")
+}
+func (*UnimplementedArtifactRegistryServiceServer) GetWorkspace(context.Context, *Workspace) (*Workspace, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkspace not implemented")
+}
+func (*UnimplementedArtifactRegistryServiceServer) GetArtifactsByWorkspace(context.Context, *Workspace) (*ArtifactsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetArtifactsByWorkspace not implemented")
+}
+func (*UnimplementedArtifactRegistryServiceServer) GetArtifactsByTypeWorkspace(context.Context, *GetArtifactsByTypeWorkspaceRequest) (*ArtifactsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetArtifactsByTypeWorkspace not implemented")
+}
+func (*UnimplementedArtifactRegistryServiceServer) GetLineageByRun(context.Context, *GetLineageByRunRequest) (*ArtifactsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLineageByRun not implemented")
+}
+func (*UnimplementedArtifactRegistryServiceServer) GetLineageByModel(context.Context, *GetLineageByModelRequest) (*ArtifactsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLineageByModel not implemented")
+}
+
+func RegisterArtifactRegistryServiceServer(s *grpc.Server, srv ArtifactRegistryServiceServer) {
+	s.RegisterService(&_ArtifactRegistryService_serviceDesc, srv)
+}
+
+func _ArtifactRegistryService_GetArtifactsByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MLArtifact)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArtifactRegistryServiceServer).GetArtifactsByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/artifact_registry.ArtifactRegistryService/GetArtifactsByID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArtifactRegistryServiceServer).GetArtifactsByID(ctx, req.(*MLArtifact))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArtifactRegistryService_GetWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Workspace)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArtifactRegistryServiceServer).GetWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/artifact_registry.ArtifactRegistryService/GetWorkspace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArtifactRegistryServiceServer).GetWorkspace(ctx, req.(*Workspace))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArtifactRegistryService_GetArtifactsByWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Workspace)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArtifactRegistryServiceServer).GetArtifactsByWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/artifact_registry.ArtifactRegistryService/GetArtifactsByWorkspace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArtifactRegistryServiceServer).GetArtifactsByWorkspace(ctx, req.(*Workspace))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArtifactRegistryService_GetArtifactsByTypeWorkspace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetArtifactsByTypeWorkspaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArtifactRegistryServiceServer).GetArtifactsByTypeWorkspace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/artifact_registry.ArtifactRegistryService/GetArtifactsByTypeWorkspace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArtifactRegistryServiceServer).GetArtifactsByTypeWorkspace(ctx, req.(*GetArtifactsByTypeWorkspaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArtifactRegistryService_GetLineageByRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLineageByRunRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArtifactRegistryServiceServer).GetLineageByRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/artifact_registry.ArtifactRegistryService/GetLineageByRun",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArtifactRegistryServiceServer).GetLineageByRun(ctx, req.(*GetLineageByRunRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ArtifactRegistryService_GetLineageByModel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLineageByModelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ArtifactRegistryServiceServer).GetLineageByModel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/artifact_registry.ArtifactRegistryService/GetLineageByModel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ArtifactRegistryServiceServer).GetLineageByModel(ctx, req.(*GetLineageByModelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ArtifactRegistryService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "artifact_registry.ArtifactRegistryService",
+	HandlerType: (*ArtifactRegistryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetArtifactsByID",
+			Handler:    _ArtifactRegistryService_GetArtifactsByID_Handler,
+		},
+		{
+			MethodName: "GetWorkspace",
+			Handler:    _ArtifactRegistryService_GetWorkspace_Handler,
+		},
+		{
+			MethodName: "GetArtifactsByWorkspace",
+			Handler:    _ArtifactRegistryService_GetArtifactsByWorkspace_Handler,
+		},
+		{
+			MethodName: "GetArtifactsByTypeWorkspace",
+			Handler:    _ArtifactRegistryService_GetArtifactsByTypeWorkspace_Handler,
+		},
+		{
+			MethodName: "GetLineageByRun",
+			Handler:    _ArtifactRegistryService_GetLineageByRun_Handler,
+		},
+		{
+			MethodName: "GetLineageByModel",
+			Handler:    _ArtifactRegistryService_GetLineageByModel_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "artifact-registry.proto",
+}
@@ -0,0 +1,393 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Methods to manage the aliases and tags of artifacts
+
+package artifact_registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Vernacular-ai/vcore/log"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+)
+
+const (
+	// Custom properties holding the JSON encoded lists of aliases and tags of
+	// an artifact
+	ALIASES_PROPERTY = "aliases"
+	TAGS_PROPERTY    = "tags"
+)
+
+// validAlias matches aliases such as champion or prod-eu.1
+var validAlias = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// SetAlias points an alias to an artifact of this workspace, so that it can be
+// referenced as name@alias, e.g. fraud-model@champion. An alias designates a
+// single version of an artifact name in a workspace: it is removed from the
+// version it pointed to, if any.
+//
+// The artifacts are only written if MLMD did not update them since they were
+// read, the call is retried otherwise. Two versions given an alias which no
+// version holds yet may still both get it when done concurrently,
+// ResolveAlias then returns the one of the highest ID.
+func (workspace Workspace) SetAlias(artifactId int64, alias string) (*pb.ArtifactData, error) {
+	return workspace.SetAliasWithContext(context.Background(), artifactId, alias)
+}
+
+// SetAliasWithContext is SetAlias using the provided context for deadlines
+// and cancellation.
+func (workspace Workspace) SetAliasWithContext(ctx context.Context, artifactId int64, alias string) (*pb.ArtifactData, error) {
+	var artifactData *pb.ArtifactData
+
+	if !validAlias.MatchString(alias) {
+		return artifactData, newError("SetAlias", ErrInvalidArgument, "invalid alias %q", alias)
+	}
+
+	client, err := workspace.artifactStore.metadataClient()
+	if err != nil {
+		return artifactData, wrapError("SetAlias", err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = workspace.setAlias(ctx, client, artifactId, alias)
+		if !errors.Is(err, errUpdatedConcurrently) || attempt == updateAttempts {
+			break
+		}
+		log.Debugf("Alias %s was updated concurrently, retrying to set it on artifact %d", alias, artifactId)
+	}
+	if err != nil {
+		return artifactData, wrapError("SetAlias", err)
+	}
+
+	artifactList, err := workspace.artifactStore.fetchArtifacts(ctx, client, []int64{artifactId})
+	if err != nil {
+		return artifactData, wrapError("SetAlias", err)
+	}
+	return artifactList[0], nil
+}
+
+// setAlias moves an alias to an artifact if neither it nor the previous
+// holders of the alias were updated since they were read, failing with
+// errUpdatedConcurrently otherwise.
+func (workspace Workspace) setAlias(ctx context.Context, client pb.MetadataStoreServiceClient, artifactId int64, alias string) error {
+	artifact, err := workspace.fetchArtifact(ctx, client, artifactId)
+	if err != nil {
+		return err
+	}
+	holders, err := workspace.aliasHolders(ctx, client, artifactName(artifact), alias)
+	if err != nil {
+		return err
+	}
+
+	var updated []*pb.Artifact
+	for _, holder := range holders {
+		if holder.GetId() == artifactId {
+			continue
+		}
+		if err := updateListProperty(holder, ALIASES_PROPERTY, nil, []string{alias}); err != nil {
+			return err
+		}
+		updated = append(updated, holder)
+	}
+	if err := updateListProperty(artifact, ALIASES_PROPERTY, []string{alias}, nil); err != nil {
+		return err
+	}
+	updated = append(updated, artifact)
+
+	// A single call so that the alias moves at once
+	if err := putArtifactsIfUnchanged(ctx, client, updated); err != nil {
+		log.Debugf("Failed to set alias %s on artifact %d: %v", alias, artifactId, err)
+		return err
+	}
+	return nil
+}
+
+// RemoveAlias removes an alias of the named artifact.
+func (workspace Workspace) RemoveAlias(name string, alias string) error {
+	return workspace.RemoveAliasWithContext(context.Background(), name, alias)
+}
+
+// RemoveAliasWithContext is RemoveAlias using the provided context for
+// deadlines and cancellation.
+func (workspace Workspace) RemoveAliasWithContext(ctx context.Context, name string, alias string) error {
+	client, err := workspace.artifactStore.metadataClient()
+	if err != nil {
+		return wrapError("RemoveAlias", err)
+	}
+
+	holders, err := workspace.aliasHolders(ctx, client, name, alias)
+	if err != nil {
+		return wrapError("RemoveAlias", err)
+	}
+	if len(holders) == 0 {
+		return newError("RemoveAlias", ErrNotFound, "alias %s@%s does not exist", name, alias)
+	}
+
+	for _, holder := range holders {
+		if err := updateListProperty(holder, ALIASES_PROPERTY, nil, []string{alias}); err != nil {
+			return wrapError("RemoveAlias", err)
+		}
+	}
+	if _, err := client.PutArtifacts(ctx, &pb.PutArtifactsRequest{Artifacts: holders}); err != nil {
+		log.Debugf("Failed to remove alias %s@%s: %v", name, alias, err)
+		return wrapError("RemoveAlias", err)
+	}
+
+	return nil
+}
+
+// ResolveAlias returns the artifact an alias of the named artifact points to.
+func (workspace Workspace) ResolveAlias(name string, alias string) (*pb.ArtifactData, error) {
+	return workspace.ResolveAliasWithContext(context.Background(), name, alias)
+}
+
+// ResolveAliasWithContext is ResolveAlias using the provided context for
+// deadlines and cancellation.
+func (workspace Workspace) ResolveAliasWithContext(ctx context.Context, name string, alias string) (*pb.ArtifactData, error) {
+	var artifactData *pb.ArtifactData
+
+	client, err := workspace.artifactStore.metadataClient()
+	if err != nil {
+		return artifactData, wrapError("ResolveAlias", err)
+	}
+
+	holders, err := workspace.aliasHolders(ctx, client, name, alias)
+	if err != nil {
+		return artifactData, wrapError("ResolveAlias", err)
+	}
+	if len(holders) == 0 {
+		return artifactData, newError("ResolveAlias", ErrNotFound, "alias %s@%s does not exist", name, alias)
+	}
+
+	// Holders are ordered by ID, the latest wins if concurrent calls to
+	// SetAlias left several
+	artifactList, err := workspace.artifactStore.prepareArtifactsList(ctx, client, holders[len(holders)-1:])
+	if err != nil {
+		return artifactData, wrapError("ResolveAlias", err)
+	}
+	return artifactList[0], nil
+}
+
+// ParseAliasReference splits a reference such as fraud-model@champion into
+// the artifact name and the alias.
+func ParseAliasReference(reference string) (string, string, error) {
+	i := strings.LastIndexByte(reference, '@')
+	if i <= 0 || !validAlias.MatchString(reference[i+1:]) {
+		return "", "", newError("ParseAliasReference", ErrInvalidArgument, "invalid alias reference %q, expected name@alias", reference)
+	}
+	return reference[:i], reference[i+1:], nil
+}
+
+// AddTags tags an artifact of this workspace. Tags already set are ignored.
+func (workspace Workspace) AddTags(artifactId int64, tags ...string) (*pb.ArtifactData, error) {
+	return workspace.AddTagsWithContext(context.Background(), artifactId, tags...)
+}
+
+// AddTagsWithContext is AddTags using the provided context for deadlines and
+// cancellation.
+func (workspace Workspace) AddTagsWithContext(ctx context.Context, artifactId int64, tags ...string) (*pb.ArtifactData, error) {
+	artifactData, err := workspace.updateTags(ctx, artifactId, tags, nil)
+	if err != nil {
+		return artifactData, wrapError("AddTags", err)
+	}
+	return artifactData, nil
+}
+
+// RemoveTags removes tags of an artifact of this workspace. Tags which are not
+// set are ignored.
+func (workspace Workspace) RemoveTags(artifactId int64, tags ...string) (*pb.ArtifactData, error) {
+	return workspace.RemoveTagsWithContext(context.Background(), artifactId, tags...)
+}
+
+// RemoveTagsWithContext is RemoveTags using the provided context for deadlines
+// and cancellation.
+func (workspace Workspace) RemoveTagsWithContext(ctx context.Context, artifactId int64, tags ...string) (*pb.ArtifactData, error) {
+	artifactData, err := workspace.updateTags(ctx, artifactId, nil, tags)
+	if err != nil {
+		return artifactData, wrapError("RemoveTags", err)
+	}
+	return artifactData, nil
+}
+
+// ListByTag returns the artifacts of this workspace having a tag.
+func (workspace Workspace) ListByTag(tag string, filters ...Filter) (*pb.ArtifactsResponse, error) {
+	return workspace.ListByTagWithContext(context.Background(), tag, filters...)
+}
+
+// ListByTagWithContext is ListByTag using the provided context for deadlines
+// and cancellation.
+func (workspace Workspace) ListByTagWithContext(ctx context.Context, tag string, filters ...Filter) (*pb.ArtifactsResponse, error) {
+	artifactsResponse, _, err := workspace.listArtifacts(ctx, nil, ListOptions{}, append([]Filter{HasTag(tag)}, filters...))
+	if err != nil {
+		return artifactsResponse, wrapError("ListByTag", err)
+	}
+	return artifactsResponse, nil
+}
+
+// HasTag matches artifacts having a tag.
+func HasTag(tag string) Filter {
+	return func(artifact *pb.Artifact) bool {
+		tags, err := listProperty(artifact, TAGS_PROPERTY)
+		return err == nil && containsString(tags, tag)
+	}
+}
+
+func (workspace Workspace) updateTags(ctx context.Context, artifactId int64, added []string, removed []string) (*pb.ArtifactData, error) {
+	for _, tag := range append(append([]string{}, added...), removed...) {
+		if strings.TrimSpace(tag) == "" {
+			return nil, newError("updateTags", ErrInvalidArgument, "tags must not be empty")
+		}
+	}
+
+	client, err := workspace.artifactStore.metadataClient()
+	if err != nil {
+		return nil, err
+	}
+
+	artifact, err := workspace.fetchArtifact(ctx, client, artifactId)
+	if err != nil {
+		return nil, err
+	}
+	if err := updateListProperty(artifact, TAGS_PROPERTY, added, removed); err != nil {
+		return nil, err
+	}
+	if _, err := client.PutArtifacts(ctx, &pb.PutArtifactsRequest{Artifacts: []*pb.Artifact{artifact}}); err != nil {
+		log.Debugf("Failed to update the tags of artifact %d: %v", artifactId, err)
+		return nil, err
+	}
+
+	artifactList, err := workspace.artifactStore.fetchArtifacts(ctx, client, []int64{artifactId})
+	if err != nil {
+		return nil, err
+	}
+	return artifactList[0], nil
+}
+
+// fetchArtifact fetches an artifact attributed to the workspace, as listed by
+// GetArtifactsByWorkspace.
+func (workspace Workspace) fetchArtifact(ctx context.Context, client pb.MetadataStoreServiceClient, artifactId int64) (*pb.Artifact, error) {
+	response, err := client.GetArtifactsByID(ctx, &pb.GetArtifactsByIDRequest{ArtifactIds: []int64{artifactId}})
+	if err != nil {
+		log.Debugf("Failed to fetch artifact %d: %v", artifactId, err)
+		return nil, err
+	}
+	if len(response.GetArtifacts()) == 0 {
+		return nil, newError("fetchArtifact", ErrNotFound, "artifact %d does not exist", artifactId)
+	}
+
+	contextsResponse, err := client.GetContextsByArtifact(ctx, &pb.GetContextsByArtifactRequest{ArtifactId: &artifactId})
+	if err != nil {
+		log.Debugf("Failed to fetch the contexts of artifact %d: %v", artifactId, err)
+		return nil, err
+	}
+	if !containsContext(contextsResponse.GetContexts(), workspace.Id) {
+		return nil, newError("fetchArtifact", ErrNotFound, "artifact %d does not exist in workspace %s", artifactId, workspace.Name)
+	}
+	return response.GetArtifacts()[0], nil
+}
+
+// aliasHolders fetches the artifacts of the workspace with the given name
+// holding an alias, ordered by ID.
+func (workspace Workspace) aliasHolders(ctx context.Context, client pb.MetadataStoreServiceClient, name string, alias string) ([]*pb.Artifact, error) {
+	response, err := client.GetArtifactsByContext(ctx, &pb.GetArtifactsByContextRequest{ContextId: &workspace.Id})
+	if err != nil {
+		log.Debugf("Failed to fetch artifacts for workspace %s, Error: %v", workspace.Name, err)
+		return nil, err
+	}
+
+	var holders []*pb.Artifact
+	for _, artifact := range response.GetArtifacts() {
+		if artifactName(artifact) != name {
+			continue
+		}
+		aliases, err := listProperty(artifact, ALIASES_PROPERTY)
+		if err != nil {
+			return nil, err
+		}
+		if containsString(aliases, alias) {
+			holders = append(holders, artifact)
+		}
+	}
+	sort.Slice(holders, func(i, j int) bool {
+		return holders[i].GetId() < holders[j].GetId()
+	})
+	return holders, nil
+}
+
+// listProperty decodes a custom property holding a JSON encoded list of
+// strings.
+func listProperty(artifact *pb.Artifact, key string) ([]string, error) {
+	var values []string
+
+	encoded := artifact.GetCustomProperties()[key].GetStringValue()
+	if encoded == "" {
+		return values, nil
+	}
+	if err := json.Unmarshal([]byte(encoded), &values); err != nil {
+		log.Debugf("Failed to decode the %s of artifact %d: %v", key, artifact.GetId(), err)
+		return nil, err
+	}
+	return values, nil
+}
+
+// updateListProperty adds and removes values of a custom property holding a
+// JSON encoded list of strings, which is kept sorted.
+func updateListProperty(artifact *pb.Artifact, key string, added []string, removed []string) error {
+	values, err := listProperty(artifact, key)
+	if err != nil {
+		return err
+	}
+
+	var updated []string
+	for _, value := range values {
+		if !containsString(removed, value) {
+			updated = append(updated, value)
+		}
+	}
+	for _, value := range added {
+		if !containsString(updated, value) {
+			updated = append(updated, value)
+		}
+	}
+	sort.Strings(updated)
+
+	if artifact.CustomProperties == nil {
+		artifact.CustomProperties = map[string]*pb.Value{}
+	}
+	if len(updated) == 0 {
+		delete(artifact.CustomProperties, key)
+		return nil
+	}
+	encoded, err := json.Marshal(updated)
+	if err != nil {
+		return err
+	}
+	artifact.CustomProperties[key] = stringValue(string(encoded))
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -21,10 +21,12 @@ package artifact_registry
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Vernacular-ai/vcore/log"
@@ -48,60 +50,154 @@ var METRICS_ARTIFACT_TYPE_NAME = "kubeflow.org/alpha/metrics"
 // Kubeflow type name for executions
 var EXECUTION_TYPE_NAME = "kubeflow.org/alpha/execution"
 
+// Timeout applied to calls made to MLMD without a deadline, unless configured
+// otherwise with WithTimeout
+const defaultTimeout = 5000 * time.Millisecond
+
 var (
-	logLevel int
-	client   pb.MetadataStoreServiceClient
+	logLevel     int
+	logLevelOnce sync.Once
 )
 
+// errNotConnected is returned when a method is called on an MLArtifactStore or
+// Workspace which was not obtained through ArtifactStore or GetWorkspace.
+var errNotConnected = errors.New("artifact store is not connected")
+
 // MLArtifactStore type provides access to list of Go methods to fetch
 // artifacts in different ways.
+//
+// Every MLArtifactStore owns its own gRPC connection to MLMD, so several
+// stores pointing at different servers can be used concurrently. Call Close
+// once the store is no longer needed.
 type MLArtifactStore struct {
 	Host string
 	Port string
+
+	conn        *grpc.ClientConn
+	client      pb.MetadataStoreServiceClient
+	typeMapping map[string]pb.ArtifactData_ArtifactType
+	types       *TypeRegistry
+	blobStores  BlobStores
+	timeout     time.Duration
 }
 
 // Workspace type provides access to list of Go methods to fetch artifacts
 // grouped within a "workspace"
 type Workspace struct {
-	Id   int64
-	Name string
+	Id          int64
+	Name        string
+	Description string
+	Owner       string
+	CreateTime  time.Time
+
+	artifactStore MLArtifactStore
 }
 
 // ArtifactStore function instantiates the MLArtifactStore instance.
 //
 // Use this instance to call methods to fetch artifacts, lineage tracking etc.
+// Connection failures are only logged, use NewArtifactStore to handle them.
 func ArtifactStore(host string, port string) MLArtifactStore {
-	var err error
+	artifactStore, err := NewArtifactStore(WithAddress(host, port))
+	if err != nil {
+		log.Errorf(err, "Failed to create artifact store")
+	}
+
+	return artifactStore
+}
+
+// NewArtifactStore instantiates an MLArtifactStore configured with the given
+// options. Without options it connects to localhost:8080 over an insecure
+// channel.
+//
+// The connection is established in the background unless WithBlock is given,
+// use Ping to check that MLMD is reachable.
+func NewArtifactStore(opts ...Option) (MLArtifactStore, error) {
+	logLevelOnce.Do(func() {
+		var err error
+		if logLevel, err = strconv.Atoi(strings.TrimSpace(os.Getenv("LOG_LEVEL"))); err == nil {
+			log.SetLevel(logLevel)
+		}
+	})
 
-	if logLevel, err = strconv.Atoi(strings.TrimSpace(os.Getenv("LOG_LEVEL"))); err == nil {
-		log.SetLevel(logLevel)
+	options := defaultOptions()
+	for _, opt := range opts {
+		opt(options)
 	}
 
-	artifactStore := MLArtifactStore{Host: host, Port: port}
-	client = clientInit(artifactStore)
+	artifactStore := MLArtifactStore{Host: options.host, Port: options.port, typeMapping: options.typeMapping, blobStores: options.blobStores, timeout: options.timeout}
+	if options.client != nil {
+		artifactStore.client = options.client
+		artifactStore.types = newTypeRegistry(artifactStore.client, options.typeCacheTTL)
+		return artifactStore, nil
+	}
 
-	return artifactStore
+	dialOptions, err := options.grpcDialOptions()
+	if err != nil {
+		return artifactStore, err
+	}
+	artifactStore.conn, artifactStore.client, err = clientInit(artifactStore, dialOptions, options.blockTimeout)
+	if err != nil {
+		return artifactStore, err
+	}
+	artifactStore.types = newTypeRegistry(artifactStore.client, options.typeCacheTTL)
+
+	return artifactStore, nil
+}
+
+// Close closes the connection to MLMD owned by this store. Workspaces
+// obtained from the store can not be used after it is closed.
+func (artifactStore MLArtifactStore) Close() error {
+	if artifactStore.conn == nil {
+		return nil
+	}
+	return artifactStore.conn.Close()
+}
+
+// Ping checks that MLMD is reachable by making a cheap call to it.
+func (artifactStore MLArtifactStore) Ping(ctx context.Context) error {
+	client, err := artifactStore.metadataClient()
+	if err != nil {
+		return wrapError("Ping", err)
+	}
+
+	if _, err := client.GetContextTypes(ctx, &pb.GetContextTypesRequest{}); err != nil {
+		log.Debugf("Failed to ping MLMD: %v", err)
+		return wrapError("Ping", err)
+	}
+
+	return nil
 }
 
 // GetArtifactsByID fetches artifacts by list of artifact IDs
-func (artifactStore MLArtifactStore) GetArtifactsByID(artifact *pb.MLArtifact) (*pb.ArtifactsResponse, error) {
-	var artifactsResponse *pb.ArtifactsResponse
+func (artifactStore MLArtifactStore) GetArtifactsByID(artifact *pb.MLArtifact, filters ...Filter) (*pb.ArtifactsResponse, error) {
+	return artifactStore.GetArtifactsByIDWithContext(context.Background(), artifact, filters...)
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5000*time.Millisecond)
-	defer cancel()
+// GetArtifactsByIDWithContext fetches artifacts by list of artifact IDs using
+// the provided context for deadlines and cancellation.
+func (artifactStore MLArtifactStore) GetArtifactsByIDWithContext(ctx context.Context, artifact *pb.MLArtifact, filters ...Filter) (*pb.ArtifactsResponse, error) {
+	var artifactsResponse *pb.ArtifactsResponse
 
 	artifacts := &pb.GetArtifactsByIDRequest{
 		ArtifactIds: artifact.Ids,
 	}
 
-	var err error
+	client, err := artifactStore.metadataClient()
+	if err != nil {
+		return artifactsResponse, wrapError("GetArtifactsByID", err)
+	}
+
 	response, err := client.GetArtifactsByID(ctx, artifacts)
 	if err != nil {
 		log.Debugf("Failed to fetch artifacts: %v", err)
-		return artifactsResponse, err
+		return artifactsResponse, wrapError("GetArtifactsByID", err)
 	}
 
-	artifactList := prepareArtifactsList(response.Artifacts)
+	artifactList, err := artifactStore.prepareArtifactsList(ctx, client, filterArtifacts(response.GetArtifacts(), filters))
+	if err != nil {
+		return artifactsResponse, wrapError("GetArtifactsByID", err)
+	}
 
 	artifactsResponse = &pb.ArtifactsResponse{Artifacts: artifactList}
 
@@ -112,24 +208,35 @@ func (artifactStore MLArtifactStore) GetArtifactsByID(artifact *pb.MLArtifact) (
 // workspace. This is used to call methods to fetch artifacts grouped in a
 // particular workspace.
 func (artifactStore MLArtifactStore) GetWorkspace(workspace *pb.Workspace) (Workspace, error) {
-	var workspaceResponse Workspace
+	return artifactStore.GetWorkspaceWithContext(context.Background(), workspace)
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5000*time.Millisecond)
-	defer cancel()
+// GetWorkspaceWithContext is GetWorkspace using the provided context for
+// deadlines and cancellation.
+func (artifactStore MLArtifactStore) GetWorkspaceWithContext(ctx context.Context, workspace *pb.Workspace) (Workspace, error) {
+	var workspaceResponse Workspace
 
 	contextRequest := &pb.GetContextByTypeAndNameRequest{
 		TypeName:    &CONTEXT_TYPE_NAME,
 		ContextName: &workspace.Name,
 	}
 
-	var err error
+	client, err := artifactStore.metadataClient()
+	if err != nil {
+		return workspaceResponse, wrapError("GetWorkspace", err)
+	}
+
 	response, err := client.GetContextByTypeAndName(ctx, contextRequest)
 	if err != nil {
 		log.Debugf("Failed to fetch workspace: %v", err)
-		return workspaceResponse, err
+		return workspaceResponse, wrapError("GetWorkspace", err)
+	}
+	if response.Context == nil {
+		log.Debugf("Workspace %s does not exist", workspace.Name)
+		return workspaceResponse, newError("GetWorkspace", ErrNotFound, "workspace %s does not exist", workspace.Name)
 	}
 
-	workspaceResponse = Workspace{Id: response.Context.GetId(), Name: response.Context.GetName()}
+	workspaceResponse = artifactStore.workspaceFromContext(response.Context)
 	log.Debugf("Fetched workspace %s", response.Context.GetName())
 
 	return workspaceResponse, nil
@@ -137,22 +244,32 @@ func (artifactStore MLArtifactStore) GetWorkspace(workspace *pb.Workspace) (Work
 
 // GetArtifactsByWorkspace returns a list of artifacts associated with this
 // workspace.
-func (workspace Workspace) GetArtifactsByWorkspace() (*pb.ArtifactsResponse, error) {
+func (workspace Workspace) GetArtifactsByWorkspace(filters ...Filter) (*pb.ArtifactsResponse, error) {
+	return workspace.GetArtifactsByWorkspaceWithContext(context.Background(), filters...)
+}
+
+// GetArtifactsByWorkspaceWithContext is GetArtifactsByWorkspace using the
+// provided context for deadlines and cancellation.
+func (workspace Workspace) GetArtifactsByWorkspaceWithContext(ctx context.Context, filters ...Filter) (*pb.ArtifactsResponse, error) {
 	var artifactsResponse *pb.ArtifactsResponse
 
 	contextRequest := &pb.GetArtifactsByContextRequest{ContextId: &workspace.Id}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5000*time.Millisecond)
-	defer cancel()
+	client, err := workspace.artifactStore.metadataClient()
+	if err != nil {
+		return artifactsResponse, wrapError("GetArtifactsByWorkspace", err)
+	}
 
-	var err error
 	response, err := client.GetArtifactsByContext(ctx, contextRequest)
 	if err != nil {
 		log.Debugf("Failed to fetch artifacts for workspace %s, Error: %v", workspace.Name, err)
-		return artifactsResponse, err
+		return artifactsResponse, wrapError("GetArtifactsByWorkspace", err)
 	}
 
-	artifactList := prepareArtifactsList(response.GetArtifacts())
+	artifactList, err := workspace.artifactStore.prepareArtifactsList(ctx, client, filterArtifacts(response.GetArtifacts(), filters))
+	if err != nil {
+		return artifactsResponse, wrapError("GetArtifactsByWorkspace", err)
+	}
 
 	artifactsResponse = &pb.ArtifactsResponse{Artifacts: artifactList}
 
@@ -161,51 +278,41 @@ func (workspace Workspace) GetArtifactsByWorkspace() (*pb.ArtifactsResponse, err
 
 // GetArtifactsByTypeWorkspace returns a list of artifacts of a certain type
 // associated with this workspace
-func (workspace Workspace) GetArtifactsByTypeWorkspace(artifactTypeRequest *pb.ArtifactByTypeRequest) (*pb.ArtifactsResponse, error) {
-	var artifactsResponse *pb.ArtifactsResponse
-	var artifactType string
-
-	switch artifactTypeRequest.ArtifactType {
-	case pb.ArtifactByTypeRequest_DATASET:
-		artifactType = DATASET_ARTIFACT_TYPE_NAME
-	case pb.ArtifactByTypeRequest_MODEL:
-		artifactType = MODEL_ARTIFACT_TYPE_NAME
-	case pb.ArtifactByTypeRequest_METRICS:
-		artifactType = METRICS_ARTIFACT_TYPE_NAME
-	default:
-		var err error
-		log.Debugf("Artifact type %s does not exist", artifactTypeRequest.ArtifactType.Enum())
-		return artifactsResponse, err
-	}
-
-	artifactsByTypeRequest := &pb.GetArtifactsByTypeRequest{TypeName: &artifactType}
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5000*time.Millisecond)
-	defer cancel()
+//
+// The artifact type of the request selects every MLMD type mapped to it, see
+// WithArtifactTypeMapping. Set its type name to fetch a single MLMD type.
+func (workspace Workspace) GetArtifactsByTypeWorkspace(artifactTypeRequest *pb.ArtifactByTypeRequest, filters ...Filter) (*pb.ArtifactsResponse, error) {
+	return workspace.GetArtifactsByTypeWorkspaceWithContext(context.Background(), artifactTypeRequest, filters...)
+}
 
-	response, err := client.GetArtifactsByType(ctx, artifactsByTypeRequest)
+// GetArtifactsByTypeWorkspaceWithContext is GetArtifactsByTypeWorkspace using
+// the provided context for deadlines and cancellation.
+func (workspace Workspace) GetArtifactsByTypeWorkspaceWithContext(ctx context.Context, artifactTypeRequest *pb.ArtifactByTypeRequest, filters ...Filter) (*pb.ArtifactsResponse, error) {
+	artifactsResponse, _, err := workspace.listArtifactsByType(ctx, artifactTypeRequest, ListOptions{}, filters)
 	if err != nil {
-		log.Debugf("Failed to fetch artifacts for workspace %s, Error: %v", workspace.Name, err)
-		return artifactsResponse, err
+		return artifactsResponse, wrapError("GetArtifactsByTypeWorkspace", err)
 	}
 
-	artifactList := prepareFilteredArtifactsList(response.GetArtifacts(), workspace.Name)
-
-	artifactsResponse = &pb.ArtifactsResponse{Artifacts: artifactList}
-
 	return artifactsResponse, nil
 }
 
 // GetLineageByRun returns a list of artifacts associated with a Kubeflow run
-func (workspace Workspace) GetLineageByRun(artifactsByRunRequest *pb.ArtifactsByRunRequest) (*pb.ArtifactsResponse, error) {
+func (workspace Workspace) GetLineageByRun(artifactsByRunRequest *pb.ArtifactsByRunRequest, filters ...Filter) (*pb.ArtifactsResponse, error) {
+	return workspace.GetLineageByRunWithContext(context.Background(), artifactsByRunRequest, filters...)
+}
+
+// GetLineageByRunWithContext is GetLineageByRun using the provided context for
+// deadlines and cancellation.
+func (workspace Workspace) GetLineageByRunWithContext(ctx context.Context, artifactsByRunRequest *pb.ArtifactsByRunRequest, filters ...Filter) (*pb.ArtifactsResponse, error) {
 	var artifactsResponse *pb.ArtifactsResponse
 	var artifactList []*pb.ArtifactData
 
-	workspaceArtifacts, _ := workspace.GetArtifactsByWorkspace()
-	log.Debug(workspaceArtifacts)
+	workspaceArtifacts, err := workspace.GetArtifactsByWorkspaceWithContext(ctx, filters...)
+	if err != nil {
+		return artifactsResponse, wrapError("GetLineageByRun", err)
+	}
 
 	for _, artifactData := range workspaceArtifacts.GetArtifacts() {
-		log.Debug(artifactData.GetId())
 		if artifactData.GetRunId() == artifactsByRunRequest.GetRunId() {
 			artifactList = append(artifactList, artifactData)
 		}
@@ -217,16 +324,30 @@ func (workspace Workspace) GetLineageByRun(artifactsByRunRequest *pb.ArtifactsBy
 }
 
 // GetLinageByModel returns a list of artifacts associated with a Model
-func (workspace Workspace) GetLineageByModel(artifactsByModelRequest *pb.ArtifactsByModelRequest) (*pb.ArtifactsResponse, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5000*time.Millisecond)
-	defer cancel()
+func (workspace Workspace) GetLineageByModel(artifactsByModelRequest *pb.ArtifactsByModelRequest, filters ...Filter) (*pb.ArtifactsResponse, error) {
+	return workspace.GetLineageByModelWithContext(context.Background(), artifactsByModelRequest, filters...)
+}
+
+// GetLineageByModelWithContext is GetLineageByModel using the provided context
+// for deadlines and cancellation.
+func (workspace Workspace) GetLineageByModelWithContext(ctx context.Context, artifactsByModelRequest *pb.ArtifactsByModelRequest, filters ...Filter) (*pb.ArtifactsResponse, error) {
+	var artifactsResponse *pb.ArtifactsResponse
+
+	client, err := workspace.artifactStore.metadataClient()
+	if err != nil {
+		return artifactsResponse, wrapError("GetLineageByModel", err)
+	}
 
 	// All executions associated with this model
 	eventsByArtifactIdRequest := &pb.GetEventsByArtifactIDsRequest{
 		ArtifactIds: []int64{artifactsByModelRequest.GetModelId()},
 	}
 
-	response, _ := client.GetEventsByArtifactIDs(ctx, eventsByArtifactIdRequest)
+	response, err := client.GetEventsByArtifactIDs(ctx, eventsByArtifactIdRequest)
+	if err != nil {
+		log.Debugf("Failed to fetch events of model %d, Error: %v", artifactsByModelRequest.GetModelId(), err)
+		return artifactsResponse, wrapError("GetLineageByModel", err)
+	}
 
 	var executionIds []int64
 	for _, event := range response.GetEvents() {
@@ -238,7 +359,11 @@ func (workspace Workspace) GetLineageByModel(artifactsByModelRequest *pb.Artifac
 		ExecutionIds: uniqueList(executionIds),
 	}
 
-	responseEvents, _ := client.GetEventsByExecutionIDs(ctx, eventsByExecutionIdsRequest)
+	responseEvents, err := client.GetEventsByExecutionIDs(ctx, eventsByExecutionIdsRequest)
+	if err != nil {
+		log.Debugf("Failed to fetch events of executions %v, Error: %v", executionIds, err)
+		return artifactsResponse, wrapError("GetLineageByModel", err)
+	}
 
 	// All the artifacts of the events
 	var artifactIds []int64
@@ -250,24 +375,42 @@ func (workspace Workspace) GetLineageByModel(artifactsByModelRequest *pb.Artifac
 		Ids: uniqueList(artifactIds),
 	}
 
-	artifactStore := MLArtifactStore{}
-	artifactsResponse, _ := artifactStore.GetArtifactsByID(artifactsByIdsRequest)
+	artifactsResponse, err = workspace.artifactStore.GetArtifactsByIDWithContext(ctx, artifactsByIdsRequest, filters...)
+	if err != nil {
+		return artifactsResponse, wrapError("GetLineageByModel", err)
+	}
 
 	return artifactsResponse, nil
 }
 
-func clientInit(artifactStore MLArtifactStore) pb.MetadataStoreServiceClient {
-	var opts []grpc.DialOption
-	opts = append(opts, grpc.WithInsecure())
+// metadataClient returns the MLMD client owned by this store.
+func (artifactStore MLArtifactStore) metadataClient() (pb.MetadataStoreServiceClient, error) {
+	if artifactStore.client == nil {
+		return nil, errNotConnected
+	}
+	return artifactStore.client, nil
+}
+
+func clientInit(artifactStore MLArtifactStore, opts []grpc.DialOption, blockTimeout time.Duration) (*grpc.ClientConn, pb.MetadataStoreServiceClient, error) {
 	address := fmt.Sprintf("%s:%s", artifactStore.Host, artifactStore.Port)
-	conn, err := grpc.Dial(address, opts...)
+
+	ctx := context.Background()
+	if blockTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, blockTimeout)
+		defer cancel()
+		opts = append(opts, grpc.WithBlock())
+	}
+
+	conn, err := grpc.DialContext(ctx, address, opts...)
 	if err != nil {
 		log.Debugf("Failed to establish client connection: %v", err)
+		return nil, nil, fmt.Errorf("failed to connect to MLMD at %s: %w", address, err)
 	}
 
 	log.Debug("Client connected")
 
 	client := pb.NewMetadataStoreServiceClient(conn)
 
-	return client
+	return conn, client, nil
 }
@@ -0,0 +1,97 @@
+// Benchmarks against the in-memory MLMD
+package artifact_registry_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+	registry "github.com/Vernacular-ai/artifact-registry/registry"
+	"github.com/Vernacular-ai/artifact-registry/registry/mlmdtest"
+)
+
+// countingClient counts the bytes of the artifact listings returned by MLMD.
+type countingClient struct {
+	pb.MetadataStoreServiceClient
+	bytes int
+}
+
+func (client *countingClient) GetArtifactsByType(ctx context.Context, in *pb.GetArtifactsByTypeRequest, opts ...grpc.CallOption) (*pb.GetArtifactsByTypeResponse, error) {
+	response, err := client.MetadataStoreServiceClient.GetArtifactsByType(ctx, in, opts...)
+	client.bytes += proto.Size(response)
+	return response, err
+}
+
+func (client *countingClient) GetArtifactsByContext(ctx context.Context, in *pb.GetArtifactsByContextRequest, opts ...grpc.CallOption) (*pb.GetArtifactsByContextResponse, error) {
+	response, err := client.MetadataStoreServiceClient.GetArtifactsByContext(ctx, in, opts...)
+	client.bytes += proto.Size(response)
+	return response, err
+}
+
+// sharedMLMD returns an MLMD shared by many workspaces each holding models,
+// datasets and metrics.
+func sharedMLMD(workspaces int, artifactsPerType int) *mlmdtest.Store {
+	mlmd := mlmdtest.NewStore()
+	mlmd.SeedKubeflowTypes()
+	for w := 0; w < workspaces; w++ {
+		workspace := fmt.Sprintf("workspace_%d", w)
+		for i := 0; i < artifactsPerType; i++ {
+			for _, artifactType := range []string{mlmdtest.ModelType, mlmdtest.DatasetType, mlmdtest.MetricsType} {
+				mlmd.SeedArtifact(mlmdtest.Artifact{
+					Type:      artifactType,
+					Name:      fmt.Sprintf("artifact-%d", i),
+					URI:       fmt.Sprintf("gcs://my-bucket/%s/%d", workspace, i),
+					Workspace: workspace,
+					RunID:     fmt.Sprintf("run-%d", i),
+				})
+			}
+		}
+	}
+	return mlmd
+}
+
+// BenchmarkGetArtifactsByTypeWorkspace compares fetching the models of a
+// workspace with the context membership of the workspace against fetching
+// every model, as GetArtifactsByTypeWorkspace used to, on a shared MLMD.
+func BenchmarkGetArtifactsByTypeWorkspace(b *testing.B) {
+	mlmd := sharedMLMD(50, 20)
+	client := &countingClient{MetadataStoreServiceClient: mlmd}
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(client))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_0"})
+	if err != nil {
+		b.Fatal(err)
+	}
+	artifactTypeRequest := &pb.ArtifactByTypeRequest{ArtifactType: pb.ArtifactByTypeRequest_MODEL}
+
+	b.Run("workspace-context", func(b *testing.B) {
+		client.bytes = 0
+		for i := 0; i < b.N; i++ {
+			if _, err := workspace.GetArtifactsByTypeWorkspace(artifactTypeRequest); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(client.bytes)/float64(b.N), "response-B/op")
+	})
+
+	b.Run("all-workspaces", func(b *testing.B) {
+		client.bytes = 0
+		typeName := "kubeflow.org/alpha/model"
+		for i := 0; i < b.N; i++ {
+			response, err := client.GetArtifactsByType(context.Background(), &pb.GetArtifactsByTypeRequest{TypeName: &typeName})
+			if err != nil {
+				b.Fatal(err)
+			}
+			var artifacts []*pb.Artifact
+			for _, artifact := range response.GetArtifacts() {
+				if artifact.CustomProperties["__kf_workspace__"].GetStringValue() == workspace.Name {
+					artifacts = append(artifacts, artifact)
+				}
+			}
+		}
+		b.ReportMetric(float64(client.bytes)/float64(b.N), "response-B/op")
+	})
+}
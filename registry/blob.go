@@ -0,0 +1,269 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Access to the payload of artifacts in blob stores
+
+package artifact_registry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Vernacular-ai/vcore/log"
+)
+
+// BlobStore reads the objects designated by artifact URIs, such as
+// gcs://my-bucket/mnist. Implementations return errors of kind ErrNotFound for
+// missing objects.
+type BlobStore interface {
+	// Open opens the object at uri for reading.
+	Open(ctx context.Context, uri string) (io.ReadCloser, error)
+	// Stat describes the object at uri. Directories are only reported by
+	// stores which have them, such as the local filesystem.
+	Stat(ctx context.Context, uri string) (BlobInfo, error)
+	// List describes the objects under uri taken as a directory, at any depth.
+	List(ctx context.Context, uri string) ([]BlobInfo, error)
+}
+
+// BlobWriter is a BlobStore which can also write objects, as needed to
+// upload artifacts.
+type BlobWriter interface {
+	BlobStore
+	// Put stores size bytes read from reader at uri, replacing the object if
+	// it exists.
+	Put(ctx context.Context, uri string, reader io.Reader, size int64) error
+	// Delete removes the object at uri, missing objects are ignored.
+	Delete(ctx context.Context, uri string) error
+}
+
+// BlobInfo describes an object of a blob store
+type BlobInfo struct {
+	URI     string
+	Size    int64
+	ModTime time.Time
+	IsDir   bool
+}
+
+// BlobStores routes URIs to the blob store registered for their scheme. URIs
+// without a scheme are local paths.
+type BlobStores map[string]BlobStore
+
+// defaultBlobStores returns the blob stores available without configuration.
+func defaultBlobStores() BlobStores {
+	return BlobStores{"file": LocalBlobStore{}}
+}
+
+// BlobStores returns the blob stores of the store, see WithBlobStore.
+func (artifactStore MLArtifactStore) BlobStores() BlobStores {
+	if artifactStore.blobStores == nil {
+		return defaultBlobStores()
+	}
+	return artifactStore.blobStores
+}
+
+// Store returns the blob store of the scheme of uri.
+func (stores BlobStores) Store(uri string) (BlobStore, error) {
+	scheme := uriScheme(uri)
+	store, ok := stores[scheme]
+	if !ok {
+		return nil, newError("Store", ErrInvalidArgument, "no blob store for scheme %q of %s", scheme, uri)
+	}
+	return store, nil
+}
+
+// Open opens the object at uri for reading.
+func (stores BlobStores) Open(ctx context.Context, uri string) (io.ReadCloser, error) {
+	store, err := stores.Store(uri)
+	if err != nil {
+		return nil, err
+	}
+	return store.Open(ctx, uri)
+}
+
+// Writer returns the blob store of the scheme of uri if it can write objects.
+func (stores BlobStores) Writer(uri string) (BlobWriter, error) {
+	store, err := stores.Store(uri)
+	if err != nil {
+		return nil, err
+	}
+	writer, ok := store.(BlobWriter)
+	if !ok {
+		return nil, newError("Writer", ErrInvalidArgument, "blob store of scheme %q is read-only", uriScheme(uri))
+	}
+	return writer, nil
+}
+
+// Stat describes the object at uri.
+func (stores BlobStores) Stat(ctx context.Context, uri string) (BlobInfo, error) {
+	store, err := stores.Store(uri)
+	if err != nil {
+		return BlobInfo{}, err
+	}
+	return store.Stat(ctx, uri)
+}
+
+// Download copies the object at uri to the file dest, or into dest if it is a
+// directory. If uri is a directory or a prefix of objects, they are copied
+// under the directory dest, keeping their relative paths. Files are written
+// to temporary files first, so dest never holds partial files.
+func (stores BlobStores) Download(ctx context.Context, uri string, dest string) error {
+	store, err := stores.Store(uri)
+	if err != nil {
+		return err
+	}
+
+	files, err := payloadFiles(ctx, store, uri)
+	if err != nil {
+		return err
+	}
+	if files[0].relative == "" {
+		if destInfo, err := os.Stat(dest); err == nil && destInfo.IsDir() {
+			dest = filepath.Join(dest, blobBase(uri))
+		}
+		return downloadBlob(ctx, store, uri, dest)
+	}
+
+	for _, file := range files {
+		target := filepath.Join(dest, filepath.FromSlash(file.relative))
+		if relative, err := filepath.Rel(dest, target); err != nil || relative == "." || relative == ".." ||
+			strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+			return newError("Download", ErrInvalidArgument, "%s is outside of %s", file.URI, uri)
+		}
+		if err := downloadBlob(ctx, store, file.URI, target); err != nil {
+			return err
+		}
+	}
+	log.Debugf("Downloaded %d files from %s to %s", len(files), uri, dest)
+
+	return nil
+}
+
+// payloadFile is a file of the payload at a URI
+type payloadFile struct {
+	BlobInfo
+	// Slash separated path relative to the URI, empty if the URI is a single
+	// object
+	relative string
+}
+
+// payloadFiles lists the object at uri, or the objects under it if it is a
+// directory or a prefix.
+func payloadFiles(ctx context.Context, store BlobStore, uri string) ([]payloadFile, error) {
+	info, err := store.Stat(ctx, uri)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if err == nil && !info.IsDir {
+		return []payloadFile{{BlobInfo: info}}, nil
+	}
+
+	blobs, err := store.List(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	prefix := strings.TrimSuffix(uri, "/") + "/"
+	var files []payloadFile
+	for _, blob := range blobs {
+		if blob.IsDir {
+			continue
+		}
+		relative := strings.TrimPrefix(blob.URI, prefix)
+		if relative == blob.URI || relative == "" {
+			return nil, newError("payloadFiles", ErrInvalidArgument, "%s is outside of %s", blob.URI, uri)
+		}
+		files = append(files, payloadFile{BlobInfo: blob, relative: relative})
+	}
+	if len(files) == 0 {
+		return nil, newError("payloadFiles", ErrNotFound, "%s does not exist", uri)
+	}
+	return files, nil
+}
+
+// DownloadArtifact copies the payload of an artifact of this workspace to
+// dest, see BlobStores.Download.
+func (workspace Workspace) DownloadArtifact(artifactId int64, dest string) error {
+	return workspace.DownloadArtifactWithContext(context.Background(), artifactId, dest)
+}
+
+// DownloadArtifactWithContext is DownloadArtifact using the provided context
+// for deadlines and cancellation.
+func (workspace Workspace) DownloadArtifactWithContext(ctx context.Context, artifactId int64, dest string) error {
+	client, err := workspace.artifactStore.metadataClient()
+	if err != nil {
+		return wrapError("DownloadArtifact", err)
+	}
+
+	artifact, err := workspace.fetchArtifact(ctx, client, artifactId)
+	if err != nil {
+		return wrapError("DownloadArtifact", err)
+	}
+	if artifact.GetUri() == "" {
+		return newError("DownloadArtifact", ErrNotFound, "artifact %d has no URI", artifactId)
+	}
+
+	if err := workspace.artifactStore.BlobStores().Download(ctx, artifact.GetUri(), dest); err != nil {
+		return wrapError("DownloadArtifact", err)
+	}
+	return nil
+}
+
+// downloadBlob copies an object to the file dest through a temporary file.
+func downloadBlob(ctx context.Context, store BlobStore, uri string, dest string) error {
+	reader, err := store.Open(ctx, uri)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	file, err := ioutil.TempFile(filepath.Dir(dest), "."+filepath.Base(dest)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := io.Copy(file, reader); err != nil {
+		file.Close()
+		log.Debugf("Failed to download %s: %v", uri, err)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), dest)
+}
+
+// uriScheme returns the scheme of uri, "file" for local paths.
+func uriScheme(uri string) string {
+	parsed, err := url.Parse(uri)
+	// Single letters are drive names, e.g. C:\models
+	if err != nil || len(parsed.Scheme) <= 1 {
+		return "file"
+	}
+	return strings.ToLower(parsed.Scheme)
+}
+
+// blobBase returns the last element of the path of uri.
+func blobBase(uri string) string {
+	uri = strings.TrimSuffix(uri, "/")
+	return uri[strings.LastIndexAny(uri, "/\\")+1:]
+}
@@ -0,0 +1,145 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Blob store on the local filesystem
+
+package artifact_registry
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/Vernacular-ai/vcore/log"
+)
+
+// LocalBlobStore reads local paths and file:// URIs, e.g. file:///models/mnist.
+type LocalBlobStore struct{}
+
+// Open opens the file at uri for reading.
+func (LocalBlobStore) Open(ctx context.Context, uri string) (io.ReadCloser, error) {
+	file, err := os.Open(localPath(uri))
+	if err != nil {
+		return nil, localError("Open", uri, err)
+	}
+	return file, nil
+}
+
+// Stat describes the file or directory at uri.
+func (LocalBlobStore) Stat(ctx context.Context, uri string) (BlobInfo, error) {
+	info, err := os.Stat(localPath(uri))
+	if err != nil {
+		return BlobInfo{}, localError("Stat", uri, err)
+	}
+	return BlobInfo{URI: uri, Size: info.Size(), ModTime: info.ModTime(), IsDir: info.IsDir()}, nil
+}
+
+// List describes the files under the directory at uri.
+func (LocalBlobStore) List(ctx context.Context, uri string) ([]BlobInfo, error) {
+	root := localPath(uri)
+
+	var blobs []BlobInfo
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			if !info.IsDir() {
+				return newError("List", ErrInvalidArgument, "%s is not a directory", uri)
+			}
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		relative, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		blobs = append(blobs, BlobInfo{
+			URI:     joinURI(uri, filepath.ToSlash(relative)),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+			IsDir:   info.IsDir(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, localError("List", uri, err)
+	}
+	return blobs, nil
+}
+
+// Put writes the file at uri through a temporary file, creating its
+// directory if needed.
+func (LocalBlobStore) Put(ctx context.Context, uri string, reader io.Reader, size int64) error {
+	path := localPath(uri)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return localError("Put", uri, err)
+	}
+	file, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return localError("Put", uri, err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := io.Copy(file, reader); err != nil {
+		file.Close()
+		return localError("Put", uri, err)
+	}
+	if err := file.Close(); err != nil {
+		return localError("Put", uri, err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return localError("Put", uri, err)
+	}
+	return nil
+}
+
+// Delete removes the file at uri.
+func (LocalBlobStore) Delete(ctx context.Context, uri string) error {
+	if err := os.Remove(localPath(uri)); err != nil && !os.IsNotExist(err) {
+		return localError("Delete", uri, err)
+	}
+	return nil
+}
+
+// localPath returns the path of a local path or file:// URI.
+func localPath(uri string) string {
+	if uriScheme(uri) != "file" {
+		return uri
+	}
+	if parsed, err := url.Parse(uri); err == nil && parsed.Scheme == "file" {
+		return filepath.FromSlash(parsed.Path)
+	}
+	return uri
+}
+
+// joinURI appends a slash separated relative path to uri.
+func joinURI(uri string, relative string) string {
+	if len(uri) > 0 && (uri[len(uri)-1] == '/' || uri[len(uri)-1] == filepath.Separator) {
+		return uri + relative
+	}
+	return uri + "/" + relative
+}
+
+func localError(op string, uri string, err error) error {
+	if os.IsNotExist(err) {
+		return newError(op, ErrNotFound, "%s does not exist", uri)
+	}
+	log.Debugf("Failed to access %s: %v", uri, err)
+	return wrapError(op, err)
+}
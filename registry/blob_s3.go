@@ -0,0 +1,402 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Blob store speaking the S3 API
+
+package artifact_registry
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Vernacular-ai/vcore/log"
+)
+
+// Payload hash of requests whose body is not signed
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3Config configures an S3BlobStore
+type S3Config struct {
+	// Endpoint of the S3 API, e.g. https://s3.eu-west-1.amazonaws.com,
+	// https://storage.googleapis.com for GCS or http://localhost:9000 for a
+	// local MinIO
+	Endpoint string
+	// Region the requests are signed for, us-east-1 if unset. GCS and MinIO
+	// accept any region.
+	Region string
+	// Credentials, requests are anonymous if unset. GCS needs HMAC keys.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// Address buckets as endpoint/bucket rather than bucket.endpoint, as
+	// MinIO usually requires
+	PathStyle bool
+	// Client sending the requests, http.DefaultClient if unset
+	HTTPClient *http.Client
+}
+
+// S3BlobStore reads the objects of URIs such as s3://bucket/key or
+// gcs://bucket/key from a server implementing the S3 API: AWS S3, GCS through
+// its XML API or MinIO. The scheme of the URIs is not checked, register the
+// store for the schemes it serves with WithBlobStore.
+type S3BlobStore struct {
+	config   S3Config
+	endpoint *url.URL
+	now      func() time.Time
+}
+
+// NewS3BlobStore creates an S3BlobStore.
+func NewS3BlobStore(config S3Config) (*S3BlobStore, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, newError("NewS3BlobStore", ErrInvalidArgument, "invalid endpoint %q", config.Endpoint)
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	return &S3BlobStore{config: config, endpoint: endpoint, now: time.Now}, nil
+}
+
+// Open opens the object at uri for reading.
+func (store *S3BlobStore) Open(ctx context.Context, uri string) (io.ReadCloser, error) {
+	bucket, key, err := parseBucketURI(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := store.do(ctx, http.MethodGet, bucket, key, nil, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusOK {
+		defer response.Body.Close()
+		return nil, s3Error("Open", uri, response)
+	}
+	return response.Body, nil
+}
+
+// Stat describes the object at uri. Prefixes are not objects, their Stat
+// fails with ErrNotFound.
+func (store *S3BlobStore) Stat(ctx context.Context, uri string) (BlobInfo, error) {
+	bucket, key, err := parseBucketURI(uri)
+	if err != nil {
+		return BlobInfo{}, err
+	}
+	if key == "" || strings.HasSuffix(key, "/") {
+		return BlobInfo{}, newError("Stat", ErrNotFound, "%s is not an object", uri)
+	}
+
+	response, err := store.do(ctx, http.MethodHead, bucket, key, nil, nil, 0)
+	if err != nil {
+		return BlobInfo{}, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return BlobInfo{}, s3Error("Stat", uri, response)
+	}
+
+	modTime, _ := http.ParseTime(response.Header.Get("Last-Modified"))
+	return BlobInfo{URI: uri, Size: response.ContentLength, ModTime: modTime}, nil
+}
+
+// List describes the objects under the prefix uri/.
+func (store *S3BlobStore) List(ctx context.Context, uri string) ([]BlobInfo, error) {
+	bucket, key, err := parseBucketURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	prefix := strings.TrimSuffix(key, "/") + "/"
+	if key == "" {
+		prefix = ""
+	}
+	base := strings.TrimSuffix(uri, "/") + "/"
+
+	var blobs []BlobInfo
+	continuationToken := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+
+		response, err := store.do(ctx, http.MethodGet, bucket, "", query, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		if response.StatusCode != http.StatusOK {
+			defer response.Body.Close()
+			return nil, s3Error("List", uri, response)
+		}
+
+		var listing struct {
+			Contents []struct {
+				Key          string
+				Size         int64
+				LastModified time.Time
+			}
+			IsTruncated           bool
+			NextContinuationToken string
+		}
+		err = xml.NewDecoder(response.Body).Decode(&listing)
+		response.Body.Close()
+		if err != nil {
+			log.Debugf("Failed to decode the listing of %s: %v", uri, err)
+			return nil, wrapError("List", err)
+		}
+
+		for _, object := range listing.Contents {
+			if strings.HasSuffix(object.Key, "/") {
+				// Placeholder of an empty directory
+				continue
+			}
+			blobs = append(blobs, BlobInfo{
+				URI:     base + strings.TrimPrefix(object.Key, prefix),
+				Size:    object.Size,
+				ModTime: object.LastModified,
+			})
+		}
+
+		if !listing.IsTruncated || listing.NextContinuationToken == "" {
+			return blobs, nil
+		}
+		continuationToken = listing.NextContinuationToken
+	}
+}
+
+// Put uploads the object at uri in a single request. S3 needs the size of
+// the object upfront.
+func (store *S3BlobStore) Put(ctx context.Context, uri string, reader io.Reader, size int64) error {
+	bucket, key, err := parseBucketURI(uri)
+	if err != nil {
+		return err
+	}
+	if key == "" || size < 0 {
+		return newError("Put", ErrInvalidArgument, "can not upload %s of size %d", uri, size)
+	}
+
+	response, err := store.do(ctx, http.MethodPut, bucket, key, nil, ioutil.NopCloser(reader), size)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return s3Error("Put", uri, response)
+	}
+	return nil
+}
+
+// Delete removes the object at uri.
+func (store *S3BlobStore) Delete(ctx context.Context, uri string) error {
+	bucket, key, err := parseBucketURI(uri)
+	if err != nil {
+		return err
+	}
+
+	response, err := store.do(ctx, http.MethodDelete, bucket, key, nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusNoContent && response.StatusCode != http.StatusNotFound {
+		return s3Error("Delete", uri, response)
+	}
+	return nil
+}
+
+// do sends a signed request for an object, or for the bucket if key is empty.
+// The body, if any, is size bytes long.
+func (store *S3BlobStore) do(ctx context.Context, method string, bucket string, key string, query url.Values, body io.ReadCloser, size int64) (*http.Response, error) {
+	requestURL := *store.endpoint
+	basePath := strings.TrimSuffix(requestURL.Path, "/")
+	if store.config.PathStyle {
+		requestURL.Path = basePath + "/" + bucket + "/" + key
+	} else {
+		requestURL.Host = bucket + "." + requestURL.Host
+		requestURL.Path = basePath + "/" + key
+	}
+	requestURL.RawPath = s3Escape(requestURL.Path, true)
+	requestURL.RawQuery = canonicalQuery(query)
+
+	request, err := http.NewRequest(method, requestURL.String(), nil)
+	if err != nil {
+		return nil, wrapError(method, err)
+	}
+	if body != nil {
+		request.Body = body
+		request.ContentLength = size
+		if size == 0 {
+			request.Body = http.NoBody
+		}
+	}
+	request = request.WithContext(ctx)
+	store.sign(request, unsignedPayload)
+
+	response, err := store.config.HTTPClient.Do(request)
+	if err != nil {
+		log.Debugf("Failed to send %s %s: %v", method, requestURL.String(), err)
+		return nil, wrapError(method, err)
+	}
+	return response, nil
+}
+
+// sign signs a request with AWS Signature Version 4, see
+// https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-authenticating-requests.html
+func (store *S3BlobStore) sign(request *http.Request, payloadHash string) {
+	now := store.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if store.config.SessionToken != "" {
+		request.Header.Set("X-Amz-Security-Token", store.config.SessionToken)
+	}
+	if store.config.AccessKeyID == "" {
+		return
+	}
+
+	headers := map[string]string{"host": request.URL.Host}
+	for name, values := range request.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-") || name == "range" || name == "content-type" || name == "content-md5" {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		s3Escape(request.URL.Path, true),
+		canonicalQuery(request.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := now.Format("20060102") + "/" + store.config.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	signingKey := hmacSHA256([]byte("AWS4"+store.config.SecretAccessKey), now.Format("20060102"))
+	signingKey = hmacSHA256(signingKey, store.config.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	request.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		store.config.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// parseBucketURI splits a URI such as s3://bucket/key in bucket and key.
+func parseBucketURI(uri string) (string, string, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Host == "" {
+		return "", "", newError("parseBucketURI", ErrInvalidArgument, "invalid bucket URI %q", uri)
+	}
+	return parsed.Host, strings.TrimPrefix(parsed.Path, "/"), nil
+}
+
+// canonicalQuery encodes a query with its keys sorted as signed by AWS.
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string{}, query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, s3Escape(key, false)+"="+s3Escape(value, false))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// s3Escape percent-encodes every byte but the unreserved characters, and
+// slashes if keepSlash is set.
+func s3Escape(value string, keepSlash bool) string {
+	var escaped strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9',
+			b == '-', b == '_', b == '.', b == '~', b == '/' && keepSlash:
+			escaped.WriteByte(b)
+		default:
+			escaped.WriteString("%" + strings.ToUpper(strconv.FormatUint(uint64(b)>>4, 16)+strconv.FormatUint(uint64(b)&15, 16)))
+		}
+	}
+	return escaped.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3Error converts an error response of the S3 API.
+func s3Error(op string, uri string, response *http.Response) error {
+	var s3Response struct {
+		Code    string
+		Message string
+	}
+	body, _ := ioutil.ReadAll(io.LimitReader(response.Body, 64*1024))
+	xml.Unmarshal(body, &s3Response)
+
+	message := fmt.Sprintf("%s: %s %s %s", uri, response.Status, s3Response.Code, s3Response.Message)
+	log.Debugf("S3 request failed, %s", message)
+
+	switch response.StatusCode {
+	case http.StatusNotFound:
+		return newError(op, ErrNotFound, "%s", message)
+	case http.StatusBadRequest:
+		return newError(op, ErrInvalidArgument, "%s", message)
+	case http.StatusConflict, http.StatusPreconditionFailed:
+		return newError(op, ErrConflict, "%s", message)
+	case http.StatusServiceUnavailable, http.StatusBadGateway:
+		return newError(op, ErrUnavailable, "%s", message)
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return newError(op, ErrTimeout, "%s", message)
+	}
+	return newError(op, nil, "%s", message)
+}
@@ -0,0 +1,362 @@
+package artifact_registry_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+	registry "github.com/Vernacular-ai/artifact-registry/registry"
+	"github.com/Vernacular-ai/artifact-registry/registry/blobtest"
+	"github.com/Vernacular-ai/artifact-registry/registry/mlmdtest"
+)
+
+// s3Store returns an S3 blob store backed by the in-memory server.
+func s3Store(t testing.TB, server *blobtest.S3Server) *registry.S3BlobStore {
+	store, err := registry.NewS3BlobStore(registry.S3Config{
+		Endpoint:        server.URL(),
+		AccessKeyID:     "minio",
+		SecretAccessKey: "minio123",
+		PathStyle:       true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func readFile(t testing.TB, path string) string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestDownloadArtifactFromS3(t *testing.T) {
+	server := blobtest.NewS3Server("minio")
+	defer server.Close()
+	server.PutObject("my-bucket", "mnist/saved_model.pb", []byte("graph"))
+	server.PutObject("my-bucket", "mnist/variables/variables.index", []byte("index"))
+	server.PutObject("my-bucket", "mnist-data", []byte("images"))
+	server.PutObject("my-bucket", "mnist-eval.csv", []byte("accuracy,0.98"))
+
+	artifactStore, _ := registry.NewArtifactStore(
+		registry.WithClient(exampleMLMD()),
+		registry.WithBlobStore(s3Store(t, server), "gcs", "s3"),
+	)
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dest, err := ioutil.TempDir("", "artifacts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dest)
+
+	// The model URI is a prefix, the sibling mnist-data object is not part of
+	// it
+	if err := workspace.DownloadArtifact(2, filepath.Join(dest, "model")); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, filepath.Join(dest, "model", "saved_model.pb")); got != "graph" {
+		t.Errorf("saved_model.pb = %q", got)
+	}
+	if got := readFile(t, filepath.Join(dest, "model", "variables", "variables.index")); got != "index" {
+		t.Errorf("variables.index = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "model", "mnist-data")); !os.IsNotExist(err) {
+		t.Errorf("sibling object downloaded: %v", err)
+	}
+
+	// A single object is written into an existing directory
+	if err := workspace.DownloadArtifact(3, dest); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, filepath.Join(dest, "mnist-eval.csv")); got != "accuracy,0.98" {
+		t.Errorf("mnist-eval.csv = %q", got)
+	}
+
+	if err := workspace.DownloadArtifact(4, dest); !errors.Is(err, registry.ErrNotFound) {
+		t.Errorf("missing payload error = %v, want ErrNotFound", err)
+	}
+	if err := workspace.DownloadArtifact(5, dest); !errors.Is(err, registry.ErrNotFound) {
+		t.Errorf("other workspace error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestDownloadArtifactFromLocalFilesystem(t *testing.T) {
+	src, err := ioutil.TempDir("", "models")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+	if err := os.MkdirAll(filepath.Join(src, "tokenizer", "assets"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	ioutil.WriteFile(filepath.Join(src, "tokenizer", "vocab.txt"), []byte("hello"), 0644)
+	ioutil.WriteFile(filepath.Join(src, "tokenizer", "assets", "merges.txt"), []byte("he llo"), 0644)
+
+	mlmd := exampleMLMD()
+	mlmd.SeedArtifact(mlmdtest.Artifact{Type: mlmdtest.ModelType, Name: "tokenizer", URI: "file://" + filepath.ToSlash(filepath.Join(src, "tokenizer")), Workspace: "workspace_1"})
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	workspace, _ := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	tokenizers, _, err := workspace.ListArtifacts(registry.ListOptions{}, registry.NameMatches("tokenizer"))
+	if err != nil || len(tokenizers.GetArtifacts()) != 1 {
+		t.Fatalf("tokenizers = %v, %v", tokenizers, err)
+	}
+
+	dest := filepath.Join(src, "download")
+	if err := workspace.DownloadArtifact(tokenizers.GetArtifacts()[0].GetId(), dest); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, filepath.Join(dest, "assets", "merges.txt")); got != "he llo" {
+		t.Errorf("merges.txt = %q", got)
+	}
+
+	// Into the working directory
+	workingDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cwd := filepath.Join(src, "cwd")
+	os.Mkdir(cwd, 0755)
+	if err := os.Chdir(cwd); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(workingDir)
+	if err := workspace.DownloadArtifact(tokenizers.GetArtifacts()[0].GetId(), "."); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, filepath.Join(cwd, "vocab.txt")); got != "hello" {
+		t.Errorf("vocab.txt downloaded to . = %q", got)
+	}
+
+	// gcs:// has no blob store by default
+	err = workspace.DownloadArtifact(2, dest)
+	if !errors.Is(err, registry.ErrInvalidArgument) {
+		t.Errorf("unknown scheme error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestS3BlobStoreList(t *testing.T) {
+	server := blobtest.NewS3Server("")
+	defer server.Close()
+	for i := 0; i < 1005; i++ {
+		server.PutObject("shards", fmt.Sprintf("dataset/part-%04d", i), []byte{byte(i)})
+	}
+	store, err := registry.NewS3BlobStore(registry.S3Config{Endpoint: server.URL(), PathStyle: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blobs, err := store.List(context.Background(), "s3://shards/dataset")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs) != 1005 || blobs[1004].URI != "s3://shards/dataset/part-1004" || blobs[1004].Size != 1 {
+		t.Errorf("listed %d blobs, last %+v", len(blobs), blobs[len(blobs)-1])
+	}
+
+	info, err := store.Stat(context.Background(), "s3://shards/dataset/part-0001")
+	if err != nil || info.Size != 1 || info.ModTime.IsZero() {
+		t.Errorf("Stat = %+v, %v", info, err)
+	}
+	if _, err := store.Stat(context.Background(), "s3://shards/missing"); !errors.Is(err, registry.ErrNotFound) {
+		t.Errorf("missing object error = %v, want ErrNotFound", err)
+	}
+}
+
+// failingWriter fails the uploads after the first one.
+type failingWriter struct {
+	registry.BlobWriter
+	puts int
+}
+
+func (writer *failingWriter) Put(ctx context.Context, uri string, reader io.Reader, size int64) error {
+	writer.puts++
+	if writer.puts > 1 {
+		return errors.New("connection reset by peer")
+	}
+	return writer.BlobWriter.Put(ctx, uri, reader, size)
+}
+
+// modelDir writes a small saved model to a temporary directory.
+func modelDir(t testing.TB) string {
+	dir, err := ioutil.TempDir("", "model")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(filepath.Join(dir, "variables"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "saved_model.pb"), []byte("graph"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "variables", "variables.index"), []byte("index"), 0644)
+	return dir
+}
+
+func TestUploadAndRegister(t *testing.T) {
+	server := blobtest.NewS3Server("minio")
+	defer server.Close()
+	source := modelDir(t)
+	defer os.RemoveAll(source)
+
+	mlmd := exampleMLMD()
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd), registry.WithBlobStore(s3Store(t, server), "s3"))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spec := registry.UploadSpec{ArtifactSpec: registry.ArtifactSpec{Name: "fraud", Version: "1.0.0", Uri: "s3://models/fraud/1.0.0", RunId: "run-42"}}
+	artifactData, err := workspace.UploadAndRegister(source, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := server.Object("models", "fraud/1.0.0/variables/variables.index"); string(data) != "index" {
+		t.Errorf("uploaded variables.index = %q", data)
+	}
+	fields := artifactData.GetMetadata().GetFields()
+	if artifactData.GetState() != pb.ArtifactData_LIVE || artifactData.GetArtifactType() != pb.ArtifactData_MODEL ||
+		artifactData.GetRunId() != "run-42" || fields["size"].GetNumberValue() != 10 ||
+		!strings.HasPrefix(fields["digest"].GetStringValue(), "sha256:") {
+		t.Errorf("registered artifact = %v", artifactData)
+	}
+
+	// The same content uploaded as a single file has the digest of the file
+	single := filepath.Join(source, "saved_model.pb")
+	file, err := workspace.UploadAndRegister(single, registry.UploadSpec{
+		ArtifactType: pb.ArtifactData_DATASET,
+		ArtifactSpec: registry.ArtifactSpec{Name: "graph", Uri: "s3://models/graph.pb"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest := file.GetMetadata().GetFields()["digest"].GetStringValue(); digest != "sha256:"+fmt.Sprintf("%x", sha256.Sum256([]byte("graph"))) {
+		t.Errorf("file digest = %s", digest)
+	}
+
+	if _, err := workspace.UploadAndRegister(source, spec); !errors.Is(err, registry.ErrConflict) {
+		t.Errorf("second upload error = %v, want ErrConflict", err)
+	}
+	if _, err := workspace.UploadAndRegister(source, registry.UploadSpec{ArtifactSpec: registry.ArtifactSpec{Name: "fraud"}}); !errors.Is(err, registry.ErrInvalidArgument) {
+		t.Errorf("upload without URI error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestUploadAndRegisterRollsBack(t *testing.T) {
+	server := blobtest.NewS3Server("minio")
+	defer server.Close()
+	source := modelDir(t)
+	defer os.RemoveAll(source)
+
+	mlmd := exampleMLMD()
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd), registry.WithBlobStore(&failingWriter{BlobWriter: s3Store(t, server)}, "s3"))
+	workspace, _ := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+
+	_, err := workspace.UploadAndRegister(source, registry.UploadSpec{
+		ArtifactSpec: registry.ArtifactSpec{Name: "fraud", Version: "1.0.0", Uri: "s3://models/fraud/1.0.0"},
+	})
+	if err == nil {
+		t.Fatal("upload succeeded")
+	}
+
+	if _, ok := server.Object("models", "fraud/1.0.0/saved_model.pb"); ok {
+		t.Error("uploaded file was not deleted")
+	}
+	frauds, _, err := workspace.ListArtifacts(registry.ListOptions{}, registry.NameMatches("fraud"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frauds.GetArtifacts()) != 0 {
+		t.Errorf("failed upload was registered: %v", frauds.GetArtifacts())
+	}
+
+	// A failed upload of a newer version does not become the latest one
+	_, err = workspace.UploadAndRegister(source, registry.UploadSpec{
+		ArtifactSpec: registry.ArtifactSpec{Name: "MNIST", Version: "99.0.0", Uri: "s3://models/mnist/99.0.0"},
+	})
+	if err == nil {
+		t.Fatal("upload succeeded")
+	}
+	latest, err := workspace.GetLatestModel("MNIST", registry.ByVersion)
+	if err != nil || latest.GetId() != 2 {
+		t.Errorf("GetLatestModel after a failed upload = %v, %v", latest, err)
+	}
+}
+
+func TestVerifyArtifact(t *testing.T) {
+	server := blobtest.NewS3Server("minio")
+	defer server.Close()
+	source := modelDir(t)
+	defer os.RemoveAll(source)
+
+	mlmd := exampleMLMD()
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd), registry.WithBlobStore(s3Store(t, server), "s3"))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	uploaded, err := workspace.UploadAndRegister(source, registry.UploadSpec{ArtifactSpec: registry.ArtifactSpec{Name: "fraud", Uri: "s3://models/fraud/1.0.0"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verification, err := workspace.VerifyArtifact(uploaded.GetId())
+	if err != nil || !verification.Verified() || verification.Size != 10 {
+		t.Errorf("VerifyArtifact = %+v, %v", verification, err)
+	}
+
+	// The same payload registered from the local copy is a duplicate
+	digest, size, err := artifactStore.BlobStores().ComputeDigest(ctx, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if digest != uploaded.GetMetadata().GetFields()["digest"].GetStringValue() || size != 10 {
+		t.Errorf("ComputeDigest = %s, %d", digest, size)
+	}
+	registered, err := workspace.RegisterModel(registry.ArtifactSpec{Name: "fraud-copy", Uri: source, Digest: strings.ToUpper(strings.TrimPrefix(digest, "sha256:")), Size: size})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorded := registered.GetMetadata().GetFields()["digest"].GetStringValue(); recorded != digest {
+		t.Errorf("recorded digest = %s, want %s", recorded, digest)
+	}
+	duplicates, err := workspace.FindByDigest(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(duplicates.GetArtifacts()) != 2 {
+		t.Errorf("FindByDigest returned %d artifacts, want 2", len(duplicates.GetArtifacts()))
+	}
+
+	// A digest recorded without its size is verified on the digest alone
+	unsized, err := workspace.RegisterModel(registry.ArtifactSpec{Name: "fraud-unsized", Uri: source, Digest: digest})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verification, err = workspace.VerifyArtifact(unsized.GetId())
+	if err != nil || !verification.Verified() || verification.ExpectedSize != 0 {
+		t.Errorf("VerifyArtifact without recorded size = %+v, %v", verification, err)
+	}
+
+	server.PutObject("models", "fraud/1.0.0/saved_model.pb", []byte("tampered"))
+	verification, err = workspace.VerifyArtifact(uploaded.GetId())
+	if err != nil || verification.Verified() || verification.Digest == verification.ExpectedDigest || verification.Size != 13 {
+		t.Errorf("VerifyArtifact of a modified payload = %+v, %v", verification, err)
+	}
+
+	if _, err := workspace.VerifyArtifact(2); !errors.Is(err, registry.ErrInvalidArgument) {
+		t.Errorf("VerifyArtifact without digest error = %v, want ErrInvalidArgument", err)
+	}
+	if _, err := workspace.RegisterModel(registry.ArtifactSpec{Name: "fraud", Uri: source, Digest: "md5:abc"}); !errors.Is(err, registry.ErrInvalidArgument) {
+		t.Errorf("RegisterModel with an invalid digest error = %v, want ErrInvalidArgument", err)
+	}
+}
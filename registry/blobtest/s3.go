@@ -0,0 +1,224 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Package blobtest provides an in-memory server implementing the part of the
+// S3 API used by the artifact registry, to test blob stores without MinIO or
+// cloud credentials.
+//
+//	server := blobtest.NewS3Server("ACCESS_KEY")
+//	defer server.Close()
+//	server.PutObject("my-bucket", "mnist/saved_model.pb", []byte("..."))
+//
+//	store, _ := registry.NewS3BlobStore(registry.S3Config{
+//		Endpoint:        server.URL(),
+//		AccessKeyID:     "ACCESS_KEY",
+//		SecretAccessKey: "secret",
+//		PathStyle:       true,
+//	})
+package blobtest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// S3Server serves buckets from memory with path-style addressing.
+// Signatures are not verified, but requests must be signed with the access key
+// given to NewS3Server if it is not empty.
+type S3Server struct {
+	server    *httptest.Server
+	accessKey string
+
+	mu      sync.Mutex
+	objects map[string]object
+	calls   map[string]int
+}
+
+type object struct {
+	data    []byte
+	modTime time.Time
+}
+
+// NewS3Server starts a server, Close it once done.
+func NewS3Server(accessKey string) *S3Server {
+	s3 := &S3Server{
+		accessKey: accessKey,
+		objects:   make(map[string]object),
+		calls:     make(map[string]int),
+	}
+	s3.server = httptest.NewServer(http.HandlerFunc(s3.serveHTTP))
+	return s3
+}
+
+// URL returns the endpoint of the server.
+func (s3 *S3Server) URL() string {
+	return s3.server.URL
+}
+
+// Close stops the server.
+func (s3 *S3Server) Close() {
+	s3.server.Close()
+}
+
+// PutObject stores an object.
+func (s3 *S3Server) PutObject(bucket string, key string, data []byte) {
+	s3.mu.Lock()
+	defer s3.mu.Unlock()
+	s3.objects[bucket+"/"+key] = object{data: append([]byte{}, data...), modTime: time.Now().UTC()}
+}
+
+// Object returns the content of an object.
+func (s3 *S3Server) Object(bucket string, key string) ([]byte, bool) {
+	s3.mu.Lock()
+	defer s3.mu.Unlock()
+	stored, ok := s3.objects[bucket+"/"+key]
+	return stored.data, ok
+}
+
+// Calls returns the number of requests received with the given method, e.g.
+// "GET".
+func (s3 *S3Server) Calls(method string) int {
+	s3.mu.Lock()
+	defer s3.mu.Unlock()
+	return s3.calls[method]
+}
+
+func (s3 *S3Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s3.mu.Lock()
+	s3.calls[r.Method]++
+	s3.mu.Unlock()
+
+	if s3.accessKey != "" && !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential="+s3.accessKey+"/") {
+		writeError(w, http.StatusForbidden, "AccessDenied", "Missing or invalid signature")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	bucket, key := path, ""
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		bucket, key = path[:i], path[i+1:]
+	}
+	if bucket == "" {
+		writeError(w, http.StatusBadRequest, "InvalidBucketName", "Missing bucket")
+		return
+	}
+
+	switch {
+	case key == "" && r.Method == http.MethodGet:
+		s3.list(w, r, bucket)
+	case key != "" && (r.Method == http.MethodGet || r.Method == http.MethodHead):
+		s3.get(w, r, bucket, key)
+	case key != "" && r.Method == http.MethodPut:
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+			return
+		}
+		s3.PutObject(bucket, key, data)
+		w.WriteHeader(http.StatusOK)
+	case key != "" && r.Method == http.MethodDelete:
+		s3.mu.Lock()
+		delete(s3.objects, bucket+"/"+key)
+		s3.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", r.Method+" is not supported")
+	}
+}
+
+func (s3 *S3Server) get(w http.ResponseWriter, r *http.Request, bucket string, key string) {
+	s3.mu.Lock()
+	stored, ok := s3.objects[bucket+"/"+key]
+	s3.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+		return
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(stored.data)))
+	w.Header().Set("Last-Modified", stored.modTime.Format(http.TimeFormat))
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		w.Write(stored.data)
+	}
+}
+
+// list implements ListObjectsV2, paging every max-keys objects, 1000 by
+// default.
+func (s3 *S3Server) list(w http.ResponseWriter, r *http.Request, bucket string) {
+	query := r.URL.Query()
+	if query.Get("list-type") != "2" {
+		writeError(w, http.StatusNotImplemented, "NotImplemented", "Only ListObjectsV2 is supported")
+		return
+	}
+	prefix := query.Get("prefix")
+	maxKeys := 1000
+	if value, err := strconv.Atoi(query.Get("max-keys")); err == nil && value > 0 {
+		maxKeys = value
+	}
+	start := query.Get("continuation-token")
+
+	s3.mu.Lock()
+	var keys []string
+	for name := range s3.objects {
+		if strings.HasPrefix(name, bucket+"/"+prefix) && name > bucket+"/"+start {
+			keys = append(keys, strings.TrimPrefix(name, bucket+"/"))
+		}
+	}
+	sort.Strings(keys)
+
+	type content struct {
+		Key          string
+		Size         int
+		LastModified string
+	}
+	var response struct {
+		XMLName               xml.Name `xml:"ListBucketResult"`
+		Name                  string
+		Prefix                string
+		KeyCount              int
+		IsTruncated           bool
+		NextContinuationToken string `xml:",omitempty"`
+		Contents              []content
+	}
+	response.Name, response.Prefix = bucket, prefix
+	for i, key := range keys {
+		if i == maxKeys {
+			response.IsTruncated = true
+			response.NextContinuationToken = keys[i-1]
+			break
+		}
+		stored := s3.objects[bucket+"/"+key]
+		response.Contents = append(response.Contents, content{Key: key, Size: len(stored.data), LastModified: stored.modTime.Format(time.RFC3339)})
+	}
+	response.KeyCount = len(response.Contents)
+	s3.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/xml")
+	fmt.Fprint(w, xml.Header)
+	xml.NewEncoder(w).Encode(response)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, code string, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(statusCode)
+	fmt.Fprintf(w, "%s<Error><Code>%s</Code><Message>%s</Message></Error>", xml.Header, code, message)
+}
@@ -0,0 +1,149 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Content digests of artifact payloads
+
+package artifact_registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Vernacular-ai/vcore/log"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+)
+
+const (
+	// Custom properties holding the digest of the payload of an artifact,
+	// e.g. sha256:9f86d08..., and its size in bytes
+	DIGEST_PROPERTY = "digest"
+	SIZE_PROPERTY   = "size"
+
+	// Prefix of SHA-256 digests
+	sha256Prefix = "sha256:"
+)
+
+var sha256Sum = regexp.MustCompile("^[0-9a-f]{64}$")
+
+// fileDigest is the digest of a file of a payload
+type fileDigest struct {
+	// Slash separated path relative to the root of the payload, empty if the
+	// payload is a single file
+	Path string
+	// Hex encoded SHA-256 of the content
+	Sum  string
+	Size int64
+}
+
+// payloadDigest returns the digest and size of a payload. The digest of a
+// single file is the SHA-256 of its content. The digest of a directory is the
+// SHA-256 of the list of its files sorted by path, one "<sha256> <size>
+// <path>" line per file, so it does not depend on the order of listings.
+func payloadDigest(files []fileDigest) (string, int64) {
+	var size int64
+	for _, file := range files {
+		size += file.Size
+	}
+	if len(files) == 1 && files[0].Path == "" {
+		return sha256Prefix + files[0].Sum, size
+	}
+
+	sorted := append([]fileDigest{}, files...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Path < sorted[j].Path
+	})
+	manifest := sha256.New()
+	for _, file := range sorted {
+		fmt.Fprintf(manifest, "%s %d %s\n", file.Sum, file.Size, file.Path)
+	}
+	return sha256Prefix + hex.EncodeToString(manifest.Sum(nil)), size
+}
+
+// ComputeDigest hashes the payload at uri, a single object or all the objects
+// under a directory or prefix, and returns its digest and size as recorded
+// by UploadAndRegister, e.g. to fill the Digest and Size of an ArtifactSpec.
+func (stores BlobStores) ComputeDigest(ctx context.Context, uri string) (string, int64, error) {
+	store, err := stores.Store(uri)
+	if err != nil {
+		return "", 0, wrapError("ComputeDigest", err)
+	}
+	files, err := payloadFiles(ctx, store, uri)
+	if err != nil {
+		return "", 0, wrapError("ComputeDigest", err)
+	}
+
+	var digests []fileDigest
+	for _, file := range files {
+		sum, size, err := blobDigest(ctx, store, file.URI)
+		if err != nil {
+			return "", 0, wrapError("ComputeDigest", err)
+		}
+		digests = append(digests, fileDigest{Path: file.relative, Sum: sum, Size: size})
+	}
+	digest, size := payloadDigest(digests)
+	return digest, size, nil
+}
+
+// blobDigest returns the hex encoded SHA-256 and the size of an object.
+func blobDigest(ctx context.Context, store BlobStore, uri string) (string, int64, error) {
+	reader, err := store.Open(ctx, uri)
+	if err != nil {
+		return "", 0, err
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, reader)
+	if err != nil {
+		log.Debugf("Failed to read %s: %v", uri, err)
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// normalizeDigest accepts a SHA-256 digest with or without the sha256:
+// prefix, in any case, and returns it prefixed and lower case.
+func normalizeDigest(digest string) (string, error) {
+	sum := strings.ToLower(strings.TrimSpace(digest))
+	sum = strings.TrimPrefix(sum, sha256Prefix)
+	if !sha256Sum.MatchString(sum) {
+		return "", newError("normalizeDigest", ErrInvalidArgument, "%q is not a SHA-256 digest", digest)
+	}
+	return sha256Prefix + sum, nil
+}
+
+// artifactDigest returns the recorded digest and size of an artifact, which
+// are properties or custom properties depending on the artifact type.
+func artifactDigest(artifact *pb.Artifact) (string, int64, bool) {
+	digest, ok := artifactProperty(artifact, DIGEST_PROPERTY)
+	if !ok || digest.GetStringValue() == "" {
+		return "", 0, false
+	}
+	size, _ := artifactProperty(artifact, SIZE_PROPERTY)
+	return digest.GetStringValue(), size.GetIntValue(), true
+}
+
+func artifactProperty(artifact *pb.Artifact, key string) (*pb.Value, bool) {
+	if value, ok := artifact.GetProperties()[key]; ok {
+		return value, true
+	}
+	value, ok := artifact.GetCustomProperties()[key]
+	return value, ok
+}
@@ -0,0 +1,131 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Errors returned by the registry
+
+package artifact_registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Kinds of errors returned by the registry, check for them with errors.Is.
+var (
+	// The requested workspace, artifact or type does not exist
+	ErrNotFound = errors.New("not found")
+	// MLMD can not be reached
+	ErrUnavailable = errors.New("unavailable")
+	// The request is malformed or refers to an unsupported value
+	ErrInvalidArgument = errors.New("invalid argument")
+	// The call did not complete before its deadline
+	ErrTimeout = errors.New("timeout")
+	// The request conflicts with the current state, e.g. another version of
+	// the model is in Production or the upload destination already exists
+	ErrConflict = errors.New("conflict")
+)
+
+// Error is returned by the registry methods. It records the failed operation
+// and the kind of failure along with the underlying error.
+type Error struct {
+	// Operation which failed, e.g. GetLineageByModel
+	Op string
+	// One of the Err* kinds, nil if the failure could not be classified
+	Kind error
+	// Underlying error, usually returned by MLMD
+	Err error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %v", e.Op, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error is of the target kind.
+func (e *Error) Is(target error) bool {
+	return e.Kind != nil && e.Kind == target
+}
+
+// GRPCStatus returns the gRPC status of the underlying error so that
+// status.FromError and status.Code keep working on wrapped errors.
+func (e *Error) GRPCStatus() *status.Status {
+	var grpcError interface{ GRPCStatus() *status.Status }
+	if errors.As(e.Err, &grpcError) {
+		return grpcError.GRPCStatus()
+	}
+
+	code := codes.Unknown
+	switch e.Kind {
+	case ErrNotFound:
+		code = codes.NotFound
+	case ErrUnavailable:
+		code = codes.Unavailable
+	case ErrInvalidArgument:
+		code = codes.InvalidArgument
+	case ErrTimeout:
+		code = codes.DeadlineExceeded
+	case ErrConflict:
+		code = codes.FailedPrecondition
+	}
+	return status.New(code, e.Error())
+}
+
+// wrapError annotates err with the operation which failed and classifies it
+// by its gRPC status code.
+func wrapError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Op: op, Kind: errorKind(err), Err: err}
+}
+
+// newError creates an error of the given kind for a failed operation.
+func newError(op string, kind error, format string, args ...interface{}) error {
+	return &Error{Op: op, Kind: kind, Err: fmt.Errorf(format, args...)}
+}
+
+func errorKind(err error) error {
+	for _, kind := range []error{ErrNotFound, ErrUnavailable, ErrInvalidArgument, ErrTimeout, ErrConflict} {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+	if errors.Is(err, errNotConnected) {
+		return ErrUnavailable
+	}
+
+	switch status.Code(err) {
+	case codes.NotFound:
+		return ErrNotFound
+	case codes.Unavailable:
+		return ErrUnavailable
+	case codes.InvalidArgument, codes.OutOfRange:
+		return ErrInvalidArgument
+	case codes.DeadlineExceeded:
+		return ErrTimeout
+	case codes.AlreadyExists, codes.FailedPrecondition, codes.Aborted:
+		return ErrConflict
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package artifact_registry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestWrapErrorKinds(t *testing.T) {
+	tests := []struct {
+		err  error
+		kind error
+	}{
+		{status.Error(codes.NotFound, "no such artifact"), ErrNotFound},
+		{status.Error(codes.Unavailable, "connection refused"), ErrUnavailable},
+		{status.Error(codes.InvalidArgument, "bad id"), ErrInvalidArgument},
+		{status.Error(codes.DeadlineExceeded, "too slow"), ErrTimeout},
+		{status.Error(codes.AlreadyExists, "duplicate name"), ErrConflict},
+		{context.DeadlineExceeded, ErrTimeout},
+		{errNotConnected, ErrUnavailable},
+	}
+
+	for _, test := range tests {
+		err := wrapError("GetLineageByModel", wrapError("GetArtifactsByID", test.err))
+		if !errors.Is(err, test.kind) {
+			t.Errorf("errors.Is(%v, %v) = false", err, test.kind)
+		}
+	}
+}
+
+func TestWrapErrorKeepsStatus(t *testing.T) {
+	err := wrapError("GetWorkspace", status.Error(codes.NotFound, "no such workspace"))
+
+	if code := status.Code(err); code != codes.NotFound {
+		t.Errorf("status.Code = %v, want %v", code, codes.NotFound)
+	}
+	if err.Error() != "GetWorkspace: rpc error: code = NotFound desc = no such workspace" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+
+	err = newError("GetArtifactsByTypeWorkspace", ErrInvalidArgument, "artifact type %d does not exist", 7)
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Errorf("status.Code = %v, want %v", code, codes.InvalidArgument)
+	}
+}
@@ -0,0 +1,301 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Methods to record executions
+
+package artifact_registry
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Vernacular-ai/vcore/log"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+)
+
+// ExecutionSpec describes an execution to record in a workspace
+type ExecutionSpec struct {
+	Name  string
+	RunId string
+	// Execution state, RUNNING if unset
+	State pb.Execution_State
+
+	// Additional properties, those defined by the execution type are stored
+	// as properties and the others as custom properties
+	Properties map[string]*pb.Value
+
+	// IDs of existing artifacts read and written by the execution
+	InputIds  []int64
+	OutputIds []int64
+	// Artifacts produced by the execution, created along with it. They belong
+	// to the run of the execution unless their RunId is set
+	NewOutputs []OutputArtifact
+}
+
+// OutputArtifact describes an artifact created by RecordExecution
+type OutputArtifact struct {
+	// One of MODEL, DATASET or METRICS
+	ArtifactType pb.ArtifactData_ArtifactType
+	ArtifactSpec
+}
+
+// Execution is an execution recorded in a workspace
+type Execution struct {
+	Id    int64
+	Name  string
+	RunId string
+	State pb.Execution_State
+
+	// Artifacts created for the NewOutputs of the execution, in order
+	Outputs []*pb.ArtifactData
+}
+
+// RecordExecution creates an execution in this workspace along with its
+// output artifacts, links it to its inputs and outputs with DECLARED_INPUT
+// and DECLARED_OUTPUT events and attributes everything to the workspace.
+//
+// Everything is written in a single PutExecution call, nothing is created if
+// any part fails.
+func (workspace Workspace) RecordExecution(spec ExecutionSpec) (*Execution, error) {
+	return workspace.RecordExecutionWithContext(context.Background(), spec)
+}
+
+// RecordExecutionWithContext is RecordExecution using the provided context for
+// deadlines and cancellation.
+func (workspace Workspace) RecordExecutionWithContext(ctx context.Context, spec ExecutionSpec) (*Execution, error) {
+	var execution *Execution
+
+	client, err := workspace.artifactStore.metadataClient()
+	if err != nil {
+		return execution, wrapError("RecordExecution", err)
+	}
+
+	state := spec.State
+	if state == pb.Execution_UNKNOWN {
+		state = pb.Execution_RUNNING
+	}
+
+	executionType, err := workspace.artifactStore.getOrCreateExecutionType(ctx, client, EXECUTION_TYPE_NAME)
+	if err != nil {
+		return execution, wrapError("RecordExecution", err)
+	}
+
+	executionRecord := &pb.Execution{
+		TypeId:           executionType.Id,
+		LastKnownState:   state.Enum(),
+		Properties:       map[string]*pb.Value{},
+		CustomProperties: map[string]*pb.Value{},
+	}
+	schema := executionType.GetProperties()
+	for key, value := range spec.Properties {
+		setProperty(executionRecord.Properties, executionRecord.CustomProperties, schema, key, value)
+	}
+	if spec.Name != "" {
+		setProperty(executionRecord.Properties, executionRecord.CustomProperties, schema, "name", stringValue(spec.Name))
+	}
+	setProperty(executionRecord.Properties, executionRecord.CustomProperties, schema, "workspace", stringValue(workspace.Name))
+	executionRecord.CustomProperties["__kf_workspace__"] = stringValue(workspace.Name)
+	if spec.RunId != "" {
+		setProperty(executionRecord.Properties, executionRecord.CustomProperties, schema, "run_id", stringValue(spec.RunId))
+		executionRecord.CustomProperties["__kf_run__"] = stringValue(spec.RunId)
+	}
+
+	var artifactEventPairs []*pb.PutExecutionRequest_ArtifactAndEvent
+	for _, artifactId := range spec.InputIds {
+		artifactEventPairs = append(artifactEventPairs, existingArtifactEvent(artifactId, pb.Event_DECLARED_INPUT))
+	}
+	for _, artifactId := range spec.OutputIds {
+		artifactEventPairs = append(artifactEventPairs, existingArtifactEvent(artifactId, pb.Event_DECLARED_OUTPUT))
+	}
+
+	var outputArtifacts []*pb.Artifact
+	for _, output := range spec.NewOutputs {
+		if output.Name == "" {
+			return execution, newError("RecordExecution", ErrInvalidArgument, "output artifact name is required")
+		}
+		typeName, ok := artifactTypeName(output.ArtifactType)
+		if !ok {
+			return execution, newError("RecordExecution", ErrInvalidArgument, "artifact type %s can not be created", output.ArtifactType)
+		}
+		artifactType, err := workspace.artifactStore.getOrCreateArtifactType(ctx, client, typeName)
+		if err != nil {
+			return execution, wrapError("RecordExecution", err)
+		}
+
+		artifactSpec := output.ArtifactSpec
+		if artifactSpec.RunId == "" {
+			artifactSpec.RunId = spec.RunId
+		}
+		artifact := newArtifact(artifactType, workspace.Name, artifactSpec)
+		outputArtifacts = append(outputArtifacts, artifact)
+		artifactEventPairs = append(artifactEventPairs, &pb.PutExecutionRequest_ArtifactAndEvent{
+			Artifact: artifact,
+			Event:    &pb.Event{Type: pb.Event_DECLARED_OUTPUT.Enum()},
+		})
+	}
+
+	// The workspace is passed unchanged so that PutExecution links the
+	// execution and its artifacts to it.
+	contextsResponse, err := client.GetContextsByID(ctx, &pb.GetContextsByIDRequest{ContextIds: []int64{workspace.Id}})
+	if err != nil {
+		log.Debugf("Failed to fetch workspace %s: %v", workspace.Name, err)
+		return execution, wrapError("RecordExecution", err)
+	}
+	if len(contextsResponse.GetContexts()) == 0 {
+		return execution, newError("RecordExecution", ErrNotFound, "workspace %s does not exist", workspace.Name)
+	}
+
+	executionRequest := &pb.PutExecutionRequest{
+		Execution:          executionRecord,
+		ArtifactEventPairs: artifactEventPairs,
+		Contexts:           contextsResponse.GetContexts(),
+	}
+	response, err := client.PutExecution(ctx, executionRequest)
+	if err != nil {
+		log.Debugf("Failed to record execution %s: %v", spec.Name, err)
+		return execution, wrapError("RecordExecution", err)
+	}
+	log.Debugf("Recorded execution %s with ID %d", spec.Name, response.GetExecutionId())
+
+	// Artifact IDs are returned in the order of the artifact event pairs, the
+	// new outputs come last.
+	artifactIds := response.GetArtifactIds()
+	if len(artifactIds) != len(artifactEventPairs) {
+		return execution, newError("RecordExecution", ErrUnavailable, "MLMD returned %d artifact IDs for %d artifacts", len(artifactIds), len(artifactEventPairs))
+	}
+	outputs, err := workspace.artifactStore.fetchArtifacts(ctx, client, artifactIds[len(artifactIds)-len(outputArtifacts):])
+	if err != nil {
+		return execution, wrapError("RecordExecution", err)
+	}
+
+	execution = &Execution{
+		Id:      response.GetExecutionId(),
+		Name:    spec.Name,
+		RunId:   spec.RunId,
+		State:   state,
+		Outputs: outputs,
+	}
+
+	return execution, nil
+}
+
+// UpdateExecutionState sets the last known state of an execution, e.g. to
+// COMPLETE or FAILED once its run is over.
+func (workspace Workspace) UpdateExecutionState(executionId int64, state pb.Execution_State) error {
+	return workspace.UpdateExecutionStateWithContext(context.Background(), executionId, state)
+}
+
+// UpdateExecutionStateWithContext is UpdateExecutionState using the provided
+// context for deadlines and cancellation.
+func (workspace Workspace) UpdateExecutionStateWithContext(ctx context.Context, executionId int64, state pb.Execution_State) error {
+	if state == pb.Execution_UNKNOWN {
+		return newError("UpdateExecutionState", ErrInvalidArgument, "execution state is required")
+	}
+
+	client, err := workspace.artifactStore.metadataClient()
+	if err != nil {
+		return wrapError("UpdateExecutionState", err)
+	}
+
+	executionRecord, err := workspace.fetchExecution(ctx, client, executionId)
+	if err != nil {
+		return wrapError("UpdateExecutionState", err)
+	}
+	executionRecord.LastKnownState = state.Enum()
+
+	if _, err := client.PutExecutions(ctx, &pb.PutExecutionsRequest{Executions: []*pb.Execution{executionRecord}}); err != nil {
+		log.Debugf("Failed to update execution %d: %v", executionId, err)
+		return wrapError("UpdateExecutionState", err)
+	}
+	log.Debugf("Execution %d is %s", executionId, state)
+
+	return nil
+}
+
+// fetchExecution fetches an execution associated with this workspace.
+func (workspace Workspace) fetchExecution(ctx context.Context, client pb.MetadataStoreServiceClient, executionId int64) (*pb.Execution, error) {
+	response, err := client.GetExecutionsByID(ctx, &pb.GetExecutionsByIDRequest{ExecutionIds: []int64{executionId}})
+	if err != nil {
+		log.Debugf("Failed to fetch execution %d: %v", executionId, err)
+		return nil, err
+	}
+	if len(response.GetExecutions()) == 0 {
+		return nil, newError("fetchExecution", ErrNotFound, "execution %d does not exist", executionId)
+	}
+
+	contextsResponse, err := client.GetContextsByExecution(ctx, &pb.GetContextsByExecutionRequest{ExecutionId: &executionId})
+	if err != nil {
+		log.Debugf("Failed to fetch the contexts of execution %d: %v", executionId, err)
+		return nil, err
+	}
+	if !containsContext(contextsResponse.GetContexts(), workspace.Id) {
+		return nil, newError("fetchExecution", ErrNotFound, "execution %d does not exist in workspace %s", executionId, workspace.Name)
+	}
+	return response.GetExecutions()[0], nil
+}
+
+func existingArtifactEvent(artifactId int64, eventType pb.Event_Type) *pb.PutExecutionRequest_ArtifactAndEvent {
+	return &pb.PutExecutionRequest_ArtifactAndEvent{
+		Event: &pb.Event{ArtifactId: &artifactId, Type: eventType.Enum()},
+	}
+}
+
+// artifactTypeName returns the Kubeflow type name of an artifact type.
+func artifactTypeName(artifactType pb.ArtifactData_ArtifactType) (string, bool) {
+	switch artifactType {
+	case pb.ArtifactData_MODEL:
+		return MODEL_ARTIFACT_TYPE_NAME, true
+	case pb.ArtifactData_DATASET:
+		return DATASET_ARTIFACT_TYPE_NAME, true
+	case pb.ArtifactData_METRICS:
+		return METRICS_ARTIFACT_TYPE_NAME, true
+	}
+	return "", false
+}
+
+// getOrCreateExecutionType fetches the named execution type, registering it
+// with the Kubeflow properties if it does not exist.
+func (artifactStore MLArtifactStore) getOrCreateExecutionType(ctx context.Context, client pb.MetadataStoreServiceClient, typeName string) (*pb.ExecutionType, error) {
+	types := artifactStore.typeRegistry(client)
+	executionType, err := types.ExecutionTypeByName(ctx, typeName)
+	if err == nil {
+		return executionType, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		log.Debugf("Failed to fetch execution type %s: %v", typeName, err)
+		return nil, err
+	}
+
+	canAddFields := true
+	executionType = &pb.ExecutionType{
+		Name: &typeName,
+		Properties: map[string]pb.PropertyType{
+			"name":      pb.PropertyType_STRING,
+			"run_id":    pb.PropertyType_STRING,
+			"workspace": pb.PropertyType_STRING,
+		},
+	}
+	typeResponse, err := client.PutExecutionType(ctx, &pb.PutExecutionTypeRequest{
+		ExecutionType: executionType,
+		CanAddFields:  &canAddFields,
+	})
+	if err != nil {
+		log.Debugf("Failed to create execution type %s: %v", typeName, err)
+		return nil, err
+	}
+	executionType.Id = typeResponse.TypeId
+	types.Invalidate()
+
+	return executionType, nil
+}
@@ -0,0 +1,240 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Filters for artifact listings
+
+package artifact_registry
+
+import (
+	"path"
+	"strings"
+	"time"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+)
+
+// Filter selects the artifacts returned by a listing. Every listing method
+// accepts filters, an artifact is returned if it matches all of them.
+//
+//	workspace.GetArtifactsByTypeWorkspace(modelRequest,
+//		registry.Property("accuracy", registry.Greater, 0.9),
+//		registry.CustomProperty("tag", registry.Equal, "prod"),
+//	)
+//
+// Filters are applied to the artifacts fetched from MLMD, a page of a listing
+// may hold fewer artifacts than its size even if it is not the last one.
+type Filter func(artifact *pb.Artifact) bool
+
+// Operator compares a property to a value
+type Operator int
+
+const (
+	Equal Operator = iota
+	NotEqual
+	Less
+	LessOrEqual
+	Greater
+	GreaterOrEqual
+)
+
+// Property matches artifacts having a property comparing to value, which is
+// an int, int64, float64 or string. Numbers are compared to int and double
+// properties, strings to string properties. Artifacts without the property
+// never match.
+func Property(key string, operator Operator, value interface{}) Filter {
+	return func(artifact *pb.Artifact) bool {
+		propertyValue, ok := artifact.GetProperties()[key]
+		return ok && compareValue(propertyValue, operator, value)
+	}
+}
+
+// CustomProperty is Property for custom properties.
+func CustomProperty(key string, operator Operator, value interface{}) Filter {
+	return func(artifact *pb.Artifact) bool {
+		propertyValue, ok := artifact.GetCustomProperties()[key]
+		return ok && compareValue(propertyValue, operator, value)
+	}
+}
+
+// NameMatches matches artifacts whose name matches a glob pattern following
+// path.Match, e.g. "MNIST-*".
+func NameMatches(pattern string) Filter {
+	return func(artifact *pb.Artifact) bool {
+		matched, err := path.Match(pattern, artifactName(artifact))
+		return err == nil && matched
+	}
+}
+
+// URIPrefix matches artifacts whose URI starts with prefix.
+func URIPrefix(prefix string) Filter {
+	return func(artifact *pb.Artifact) bool {
+		return strings.HasPrefix(artifact.GetUri(), prefix)
+	}
+}
+
+// StateIn matches artifacts in one of the given states.
+func StateIn(states ...pb.Artifact_State) Filter {
+	return func(artifact *pb.Artifact) bool {
+		for _, state := range states {
+			if artifact.GetState() == state {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// CreatedBetween matches artifacts created from start included until end
+// excluded. A zero start or end leaves the range open on that side.
+func CreatedBetween(start time.Time, end time.Time) Filter {
+	return func(artifact *pb.Artifact) bool {
+		createTime := time.Unix(0, artifact.GetCreateTimeSinceEpoch()*int64(time.Millisecond))
+		if !start.IsZero() && createTime.Before(start) {
+			return false
+		}
+		if !end.IsZero() && !createTime.Before(end) {
+			return false
+		}
+		return true
+	}
+}
+
+// And matches artifacts matching all the filters.
+func And(filters ...Filter) Filter {
+	return func(artifact *pb.Artifact) bool {
+		for _, filter := range filters {
+			if !filter(artifact) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or matches artifacts matching any of the filters.
+func Or(filters ...Filter) Filter {
+	return func(artifact *pb.Artifact) bool {
+		for _, filter := range filters {
+			if filter(artifact) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not matches artifacts not matching filter.
+func Not(filter Filter) Filter {
+	return func(artifact *pb.Artifact) bool {
+		return !filter(artifact)
+	}
+}
+
+// filterArtifacts keeps the artifacts matching all the filters.
+func filterArtifacts(artifacts []*pb.Artifact, filters []Filter) []*pb.Artifact {
+	if len(filters) == 0 {
+		return artifacts
+	}
+
+	var filtered []*pb.Artifact
+	for _, artifact := range artifacts {
+		if And(filters...)(artifact) {
+			filtered = append(filtered, artifact)
+		}
+	}
+	return filtered
+}
+
+// artifactName returns the name of an artifact: the name property of the
+// Kubeflow types, the name field of KFP v2 system.* artifacts or the name
+// custom property of TFX artifacts.
+func artifactName(artifact *pb.Artifact) string {
+	if name := artifact.GetProperties()["name"].GetStringValue(); name != "" {
+		return name
+	}
+	if name := artifact.GetName(); name != "" {
+		return name
+	}
+	return artifact.GetCustomProperties()["name"].GetStringValue()
+}
+
+// artifactVersion returns the version of an artifact, a custom property for
+// types without a version property such as the Kubeflow metrics.
+func artifactVersion(artifact *pb.Artifact) string {
+	if version := artifact.GetProperties()["version"].GetStringValue(); version != "" {
+		return version
+	}
+	return artifact.GetCustomProperties()["version"].GetStringValue()
+}
+
+func compareValue(propertyValue *pb.Value, operator Operator, value interface{}) bool {
+	var comparison int
+
+	switch property := propertyValue.GetValue().(type) {
+	case *pb.Value_StringValue:
+		expected, ok := value.(string)
+		if !ok {
+			return false
+		}
+		comparison = strings.Compare(property.StringValue, expected)
+	case *pb.Value_IntValue, *pb.Value_DoubleValue:
+		expected, ok := toFloat(value)
+		if !ok {
+			return false
+		}
+		actual := float64(propertyValue.GetIntValue())
+		if _, isDouble := property.(*pb.Value_DoubleValue); isDouble {
+			actual = propertyValue.GetDoubleValue()
+		}
+		switch {
+		case actual < expected:
+			comparison = -1
+		case actual > expected:
+			comparison = 1
+		}
+	default:
+		return false
+	}
+
+	switch operator {
+	case Equal:
+		return comparison == 0
+	case NotEqual:
+		return comparison != 0
+	case Less:
+		return comparison < 0
+	case LessOrEqual:
+		return comparison <= 0
+	case Greater:
+		return comparison > 0
+	case GreaterOrEqual:
+		return comparison >= 0
+	}
+	return false
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch number := value.(type) {
+	case int:
+		return float64(number), true
+	case int32:
+		return float64(number), true
+	case int64:
+		return float64(number), true
+	case float32:
+		return float64(number), true
+	case float64:
+		return number, true
+	}
+	return 0, false
+}
@@ -0,0 +1,111 @@
+package artifact_registry_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+	registry "github.com/Vernacular-ai/artifact-registry/registry"
+	"github.com/Vernacular-ai/artifact-registry/registry/mlmdtest"
+)
+
+func TestFilters(t *testing.T) {
+	created := time.Date(2021, 3, 30, 16, 50, 45, 0, time.UTC)
+	createTime := created.UnixNano() / int64(time.Millisecond)
+	artifact := &pb.Artifact{
+		Uri:                  func(uri string) *string { return &uri }("gcs://my-bucket/mnist"),
+		State:                pb.Artifact_LIVE.Enum(),
+		CreateTimeSinceEpoch: &createTime,
+		Properties: map[string]*pb.Value{
+			"name":   mlmdtest.StringValue("MNIST-evaluation"),
+			"epochs": mlmdtest.IntValue(10),
+		},
+		CustomProperties: map[string]*pb.Value{
+			"accuracy": mlmdtest.DoubleValue(0.93),
+			"tag":      mlmdtest.StringValue("prod"),
+		},
+	}
+
+	tests := []struct {
+		name   string
+		filter registry.Filter
+		want   bool
+	}{
+		{"int equal", registry.Property("epochs", registry.Equal, 10), true},
+		{"int compared to double", registry.Property("epochs", registry.Less, 10.5), true},
+		{"string compared to number", registry.Property("name", registry.Equal, 10), false},
+		{"missing property", registry.Property("accuracy", registry.Greater, 0.9), false},
+		{"custom double", registry.CustomProperty("accuracy", registry.Greater, 0.9), true},
+		{"custom double lower", registry.CustomProperty("accuracy", registry.GreaterOrEqual, 0.95), false},
+		{"custom string", registry.CustomProperty("tag", registry.Equal, "prod"), true},
+		{"custom string not equal", registry.CustomProperty("tag", registry.NotEqual, "prod"), false},
+		{"name glob", registry.NameMatches("MNIST-*"), true},
+		{"name glob mismatch", registry.NameMatches("mnist*"), false},
+		{"uri prefix", registry.URIPrefix("gcs://my-bucket/"), true},
+		{"state", registry.StateIn(pb.Artifact_PENDING, pb.Artifact_LIVE), true},
+		{"state mismatch", registry.StateIn(pb.Artifact_DELETED), false},
+		{"created in range", registry.CreatedBetween(created, created.Add(time.Second)), true},
+		{"created before range", registry.CreatedBetween(created.Add(time.Second), time.Time{}), false},
+		{"end excluded", registry.CreatedBetween(time.Time{}, created), false},
+		{"and", registry.And(registry.NameMatches("MNIST*"), registry.CustomProperty("tag", registry.Equal, "dev")), false},
+		{"or", registry.Or(registry.NameMatches("mnist*"), registry.CustomProperty("tag", registry.Equal, "prod")), true},
+		{"not", registry.Not(registry.URIPrefix("s3://")), true},
+	}
+	for _, test := range tests {
+		if got := test.filter(artifact); got != test.want {
+			t.Errorf("%s: filter = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestListingFilters(t *testing.T) {
+	mlmd := exampleMLMD()
+	mlmd.SeedArtifact(mlmdtest.Artifact{
+		Type:             mlmdtest.ModelType,
+		Name:             "MNIST-large",
+		Workspace:        "workspace_1",
+		RunID:            "run-2",
+		CustomProperties: map[string]*pb.Value{"accuracy": mlmdtest.DoubleValue(0.95)},
+	})
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accurate := registry.CustomProperty("accuracy", registry.Greater, 0.9)
+	modelRequest := &pb.ArtifactByTypeRequest{ArtifactType: pb.ArtifactByTypeRequest_MODEL}
+
+	byType, err := workspace.GetArtifactsByTypeWorkspace(modelRequest, accurate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byWorkspace, err := workspace.GetArtifactsByWorkspace(registry.NameMatches("MNIST*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	byID, err := artifactStore.GetArtifactsByID(&pb.MLArtifact{Ids: []int64{1, 2, 3}}, registry.Not(registry.NameMatches("MNIST*")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lineage, err := workspace.GetLineageByModel(&pb.ArtifactsByModelRequest{ModelId: 2}, registry.URIPrefix("gcs://my-bucket/mnist-"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	iterated := 0
+	iterator := workspace.IterateArtifacts(context.Background(), registry.ListOptions{PageSize: 2}, accurate)
+	for iterator.Next() {
+		iterated++
+	}
+
+	counts := []int{len(byType.GetArtifacts()), len(byWorkspace.GetArtifacts()), len(byID.GetArtifacts()), len(lineage.GetArtifacts()), iterated}
+	want := []int{1, 3, 1, 2, 1}
+	for i := range counts {
+		if counts[i] != want[i] {
+			t.Errorf("filtered listing counts = %v, want %v", counts, want)
+			break
+		}
+	}
+}
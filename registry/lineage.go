@@ -0,0 +1,275 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Lineage graph traversal
+
+package artifact_registry
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Vernacular-ai/vcore/log"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+)
+
+// LineageDirection selects which way the lineage graph is walked
+type LineageDirection int
+
+const (
+	// Walk towards the executions and artifacts an artifact was produced from
+	Upstream LineageDirection = iota
+	// Walk towards the executions and artifacts produced from an artifact
+	Downstream
+	// Walk both upstream and downstream
+	Both
+)
+
+// LineageOptions configures the traversal of GetLineageGraph
+type LineageOptions struct {
+	Direction LineageDirection
+	// Maximum number of executions walked through from the artifact, zero
+	// walks the whole lineage
+	MaxHops int
+}
+
+// GetLineageGraph walks the events linking artifacts and executions starting
+// from an artifact and returns the visited subgraph.
+//
+// The graph holds the artifacts and executions reached along with their types,
+// and the events between them as edges. Walking upstream follows the outputs
+// of an execution back to its inputs, downstream the inputs forward to the
+// outputs. Both walks the two directions independently, the siblings of an
+// artifact are not part of the graph.
+func (workspace Workspace) GetLineageGraph(artifactId int64, options LineageOptions) (*pb.LineageGraph, error) {
+	return workspace.GetLineageGraphWithContext(context.Background(), artifactId, options)
+}
+
+// GetLineageGraphWithContext is GetLineageGraph using the provided context for
+// deadlines and cancellation.
+func (workspace Workspace) GetLineageGraphWithContext(ctx context.Context, artifactId int64, options LineageOptions) (*pb.LineageGraph, error) {
+	var lineageGraph *pb.LineageGraph
+
+	if options.MaxHops < 0 {
+		return lineageGraph, newError("GetLineageGraph", ErrInvalidArgument, "max hops must not be negative, got %d", options.MaxHops)
+	}
+
+	client, err := workspace.artifactStore.metadataClient()
+	if err != nil {
+		return lineageGraph, wrapError("GetLineageGraph", err)
+	}
+
+	artifactsResponse, err := client.GetArtifactsByID(ctx, &pb.GetArtifactsByIDRequest{ArtifactIds: []int64{artifactId}})
+	if err != nil {
+		log.Debugf("Failed to fetch artifact %d: %v", artifactId, err)
+		return lineageGraph, wrapError("GetLineageGraph", err)
+	}
+	if len(artifactsResponse.GetArtifacts()) == 0 {
+		return lineageGraph, newError("GetLineageGraph", ErrNotFound, "artifact %d does not exist", artifactId)
+	}
+
+	walk := newLineageWalk(artifactId)
+	switch options.Direction {
+	case Upstream:
+		err = walk.run(ctx, client, artifactId, true, options.MaxHops)
+	case Downstream:
+		err = walk.run(ctx, client, artifactId, false, options.MaxHops)
+	case Both:
+		if err = walk.run(ctx, client, artifactId, true, options.MaxHops); err == nil {
+			err = walk.run(ctx, client, artifactId, false, options.MaxHops)
+		}
+	default:
+		return lineageGraph, newError("GetLineageGraph", ErrInvalidArgument, "unknown lineage direction %d", options.Direction)
+	}
+	if err != nil {
+		return lineageGraph, wrapError("GetLineageGraph", err)
+	}
+
+	lineageGraph, err = walk.graph(ctx, client)
+	if err != nil {
+		return lineageGraph, wrapError("GetLineageGraph", err)
+	}
+
+	return lineageGraph, nil
+}
+
+// lineageWalk accumulates the nodes and edges visited by the traversal.
+type lineageWalk struct {
+	artifactIds  map[int64]bool
+	executionIds map[int64]bool
+	events       map[lineageEdge]*pb.Event
+}
+
+type lineageEdge struct {
+	artifactId  int64
+	executionId int64
+	eventType   pb.Event_Type
+}
+
+func newLineageWalk(artifactId int64) *lineageWalk {
+	return &lineageWalk{
+		artifactIds:  map[int64]bool{artifactId: true},
+		executionIds: map[int64]bool{},
+		events:       map[lineageEdge]*pb.Event{},
+	}
+}
+
+// run walks breadth first from an artifact, one execution per hop.
+func (walk *lineageWalk) run(ctx context.Context, client pb.MetadataStoreServiceClient, artifactId int64, upstream bool, maxHops int) error {
+	// Upstream an artifact is reached from the executions it is an output of,
+	// and they are reached from their inputs. Downstream is the opposite.
+	toExecution, toArtifact := isOutputEvent, isInputEvent
+	if !upstream {
+		toExecution, toArtifact = isInputEvent, isOutputEvent
+	}
+
+	visitedArtifacts := map[int64]bool{artifactId: true}
+	visitedExecutions := map[int64]bool{}
+	frontier := []int64{artifactId}
+
+	for hop := 0; len(frontier) > 0 && (maxHops == 0 || hop < maxHops); hop++ {
+		artifactEvents, err := client.GetEventsByArtifactIDs(ctx, &pb.GetEventsByArtifactIDsRequest{ArtifactIds: frontier})
+		if err != nil {
+			log.Debugf("Failed to fetch events of artifacts %v: %v", frontier, err)
+			return err
+		}
+
+		var executionIds []int64
+		for _, event := range artifactEvents.GetEvents() {
+			if !toExecution(event) {
+				continue
+			}
+			walk.addEvent(event)
+			if !visitedExecutions[event.GetExecutionId()] {
+				visitedExecutions[event.GetExecutionId()] = true
+				executionIds = append(executionIds, event.GetExecutionId())
+			}
+		}
+		if len(executionIds) == 0 {
+			break
+		}
+
+		executionEvents, err := client.GetEventsByExecutionIDs(ctx, &pb.GetEventsByExecutionIDsRequest{ExecutionIds: executionIds})
+		if err != nil {
+			log.Debugf("Failed to fetch events of executions %v: %v", executionIds, err)
+			return err
+		}
+
+		frontier = nil
+		for _, event := range executionEvents.GetEvents() {
+			if !toArtifact(event) {
+				continue
+			}
+			walk.addEvent(event)
+			if !visitedArtifacts[event.GetArtifactId()] {
+				visitedArtifacts[event.GetArtifactId()] = true
+				frontier = append(frontier, event.GetArtifactId())
+			}
+		}
+	}
+
+	return nil
+}
+
+func (walk *lineageWalk) addEvent(event *pb.Event) {
+	walk.artifactIds[event.GetArtifactId()] = true
+	walk.executionIds[event.GetExecutionId()] = true
+	walk.events[lineageEdge{event.GetArtifactId(), event.GetExecutionId(), event.GetType()}] = event
+}
+
+// graph fetches the visited nodes and their types.
+func (walk *lineageWalk) graph(ctx context.Context, client pb.MetadataStoreServiceClient) (*pb.LineageGraph, error) {
+	lineageGraph := &pb.LineageGraph{}
+
+	artifacts, err := client.GetArtifactsByID(ctx, &pb.GetArtifactsByIDRequest{ArtifactIds: sortedKeys(walk.artifactIds)})
+	if err != nil {
+		log.Debugf("Failed to fetch lineage artifacts: %v", err)
+		return nil, err
+	}
+	lineageGraph.Artifacts = artifacts.GetArtifacts()
+
+	if len(walk.executionIds) > 0 {
+		executions, err := client.GetExecutionsByID(ctx, &pb.GetExecutionsByIDRequest{ExecutionIds: sortedKeys(walk.executionIds)})
+		if err != nil {
+			log.Debugf("Failed to fetch lineage executions: %v", err)
+			return nil, err
+		}
+		lineageGraph.Executions = executions.GetExecutions()
+	}
+
+	artifactTypeIds := map[int64]bool{}
+	for _, artifact := range lineageGraph.Artifacts {
+		artifactTypeIds[artifact.GetTypeId()] = true
+	}
+	artifactTypes, err := client.GetArtifactTypesByID(ctx, &pb.GetArtifactTypesByIDRequest{TypeIds: sortedKeys(artifactTypeIds)})
+	if err != nil {
+		log.Debugf("Failed to fetch lineage artifact types: %v", err)
+		return nil, err
+	}
+	lineageGraph.ArtifactTypes = artifactTypes.GetArtifactTypes()
+
+	if len(lineageGraph.Executions) > 0 {
+		executionTypeIds := map[int64]bool{}
+		for _, execution := range lineageGraph.Executions {
+			executionTypeIds[execution.GetTypeId()] = true
+		}
+		executionTypes, err := client.GetExecutionTypesByID(ctx, &pb.GetExecutionTypesByIDRequest{TypeIds: sortedKeys(executionTypeIds)})
+		if err != nil {
+			log.Debugf("Failed to fetch lineage execution types: %v", err)
+			return nil, err
+		}
+		lineageGraph.ExecutionTypes = executionTypes.GetExecutionTypes()
+	}
+
+	for _, event := range walk.events {
+		lineageGraph.Events = append(lineageGraph.Events, event)
+	}
+	sort.Slice(lineageGraph.Events, func(i, j int) bool {
+		a, b := lineageGraph.Events[i], lineageGraph.Events[j]
+		if a.GetExecutionId() != b.GetExecutionId() {
+			return a.GetExecutionId() < b.GetExecutionId()
+		}
+		if a.GetArtifactId() != b.GetArtifactId() {
+			return a.GetArtifactId() < b.GetArtifactId()
+		}
+		return a.GetType() < b.GetType()
+	})
+
+	return lineageGraph, nil
+}
+
+func isInputEvent(event *pb.Event) bool {
+	switch event.GetType() {
+	case pb.Event_DECLARED_INPUT, pb.Event_INPUT, pb.Event_INTERNAL_INPUT:
+		return true
+	}
+	return false
+}
+
+func isOutputEvent(event *pb.Event) bool {
+	switch event.GetType() {
+	case pb.Event_DECLARED_OUTPUT, pb.Event_OUTPUT, pb.Event_INTERNAL_OUTPUT:
+		return true
+	}
+	return false
+}
+
+func sortedKeys(set map[int64]bool) []int64 {
+	keys := make([]int64, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
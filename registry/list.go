@@ -0,0 +1,260 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Paginated listings and iterators
+
+package artifact_registry
+
+import (
+	"context"
+
+	"github.com/Vernacular-ai/vcore/log"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+)
+
+// OrderField is the field a listing is ordered by
+type OrderField int
+
+const (
+	// Order by ID, the default
+	OrderByID OrderField = iota
+	OrderByCreateTime
+	OrderByLastUpdateTime
+)
+
+// ListOptions selects a page of a listing and its order
+type ListOptions struct {
+	// Maximum number of results, 20 if unset
+	PageSize int32
+	// Token returned along with the previous page, empty for the first page.
+	// The other options must not change between pages.
+	PageToken string
+
+	OrderBy    OrderField
+	Descending bool
+}
+
+// ListArtifacts returns a page of the artifacts associated with this workspace
+// along with the token of the next page, which is empty on the last page.
+func (workspace Workspace) ListArtifacts(options ListOptions, filters ...Filter) (*pb.ArtifactsResponse, string, error) {
+	return workspace.ListArtifactsWithContext(context.Background(), options, filters...)
+}
+
+// ListArtifactsWithContext is ListArtifacts using the provided context for
+// deadlines and cancellation.
+func (workspace Workspace) ListArtifactsWithContext(ctx context.Context, options ListOptions, filters ...Filter) (*pb.ArtifactsResponse, string, error) {
+	artifactsResponse, nextPageToken, err := workspace.listArtifacts(ctx, nil, options, filters)
+	if err != nil {
+		return artifactsResponse, "", wrapError("ListArtifacts", err)
+	}
+	return artifactsResponse, nextPageToken, nil
+}
+
+// ListArtifactsByType returns a page of the artifacts of a certain type
+// associated with this workspace along with the token of the next page, which
+// is empty on the last page.
+//
+// MLMD pages through all the artifacts of the workspace, a page may hold fewer
+// artifacts than PageSize even if it is not the last one.
+func (workspace Workspace) ListArtifactsByType(artifactTypeRequest *pb.ArtifactByTypeRequest, options ListOptions, filters ...Filter) (*pb.ArtifactsResponse, string, error) {
+	return workspace.ListArtifactsByTypeWithContext(context.Background(), artifactTypeRequest, options, filters...)
+}
+
+// ListArtifactsByTypeWithContext is ListArtifactsByType using the provided
+// context for deadlines and cancellation.
+func (workspace Workspace) ListArtifactsByTypeWithContext(ctx context.Context, artifactTypeRequest *pb.ArtifactByTypeRequest, options ListOptions, filters ...Filter) (*pb.ArtifactsResponse, string, error) {
+	artifactsResponse, nextPageToken, err := workspace.listArtifactsByType(ctx, artifactTypeRequest, options, filters)
+	if err != nil {
+		return artifactsResponse, "", wrapError("ListArtifactsByType", err)
+	}
+	return artifactsResponse, nextPageToken, nil
+}
+
+// listArtifactsByType fetches a page of the artifacts of the workspace and
+// keeps those of the requested types. MLMD can not filter the artifacts of a
+// context by type, but the artifacts of other workspaces are never fetched.
+func (workspace Workspace) listArtifactsByType(ctx context.Context, artifactTypeRequest *pb.ArtifactByTypeRequest, options ListOptions, filters []Filter) (*pb.ArtifactsResponse, string, error) {
+	var artifactsResponse *pb.ArtifactsResponse
+
+	typeNames, err := workspace.artifactStore.requestedTypeNames(artifactTypeRequest)
+	if err != nil {
+		return artifactsResponse, "", err
+	}
+
+	client, err := workspace.artifactStore.metadataClient()
+	if err != nil {
+		return artifactsResponse, "", err
+	}
+
+	typeIds, err := workspace.artifactStore.artifactTypeIds(ctx, client, typeNames)
+	if err != nil {
+		return artifactsResponse, "", err
+	}
+	if len(typeIds) == 0 {
+		// No artifact of these types was ever created
+		return &pb.ArtifactsResponse{}, "", nil
+	}
+
+	return workspace.listArtifacts(ctx, typeIds, options, filters)
+}
+
+// listArtifacts fetches a page of the artifacts of the workspace, keeping
+// those of the given types if any and matching the filters.
+func (workspace Workspace) listArtifacts(ctx context.Context, typeIds map[int64]bool, options ListOptions, filters []Filter) (*pb.ArtifactsResponse, string, error) {
+	var artifactsResponse *pb.ArtifactsResponse
+
+	client, err := workspace.artifactStore.metadataClient()
+	if err != nil {
+		return artifactsResponse, "", err
+	}
+
+	contextRequest := &pb.GetArtifactsByContextRequest{
+		ContextId: &workspace.Id,
+		Options:   options.listOperationOptions(),
+	}
+	response, err := client.GetArtifactsByContext(ctx, contextRequest)
+	if err != nil {
+		log.Debugf("Failed to fetch artifacts for workspace %s, Error: %v", workspace.Name, err)
+		return artifactsResponse, "", err
+	}
+
+	artifacts := response.GetArtifacts()
+	if typeIds != nil {
+		artifacts = nil
+		for _, artifact := range response.GetArtifacts() {
+			if typeIds[artifact.GetTypeId()] {
+				artifacts = append(artifacts, artifact)
+			}
+		}
+	}
+
+	artifactList, err := workspace.artifactStore.prepareArtifactsList(ctx, client, filterArtifacts(artifacts, filters))
+	if err != nil {
+		return artifactsResponse, "", err
+	}
+
+	artifactsResponse = &pb.ArtifactsResponse{Artifacts: artifactList}
+
+	return artifactsResponse, response.GetNextPageToken(), nil
+}
+
+// ArtifactIterator lazily pages through a listing of artifacts.
+//
+//	iterator := workspace.IterateArtifacts(ctx, registry.ListOptions{PageSize: 100})
+//	for iterator.Next() {
+//		artifactData := iterator.Artifact()
+//	}
+//	if err := iterator.Err(); err != nil {
+//		...
+//	}
+type ArtifactIterator struct {
+	ctx     context.Context
+	options ListOptions
+	fetch   func(ctx context.Context, options ListOptions) (*pb.ArtifactsResponse, string, error)
+
+	page []*pb.ArtifactData
+	next int
+	done bool
+	err  error
+}
+
+// IterateArtifacts returns an iterator over the artifacts associated with this
+// workspace. PageToken of the options is ignored.
+func (workspace Workspace) IterateArtifacts(ctx context.Context, options ListOptions, filters ...Filter) *ArtifactIterator {
+	return newArtifactIterator(ctx, options, func(ctx context.Context, options ListOptions) (*pb.ArtifactsResponse, string, error) {
+		return workspace.ListArtifactsWithContext(ctx, options, filters...)
+	})
+}
+
+// IterateArtifactsByType returns an iterator over the artifacts of a certain
+// type associated with this workspace. PageToken of the options is ignored.
+func (workspace Workspace) IterateArtifactsByType(ctx context.Context, artifactTypeRequest *pb.ArtifactByTypeRequest, options ListOptions, filters ...Filter) *ArtifactIterator {
+	return newArtifactIterator(ctx, options, func(ctx context.Context, options ListOptions) (*pb.ArtifactsResponse, string, error) {
+		return workspace.ListArtifactsByTypeWithContext(ctx, artifactTypeRequest, options, filters...)
+	})
+}
+
+func newArtifactIterator(ctx context.Context, options ListOptions, fetch func(ctx context.Context, options ListOptions) (*pb.ArtifactsResponse, string, error)) *ArtifactIterator {
+	options.PageToken = ""
+	return &ArtifactIterator{ctx: ctx, options: options, fetch: fetch}
+}
+
+// Next advances the iterator to the next artifact, fetching the next page when
+// needed. It returns false at the end of the listing or on error.
+func (iterator *ArtifactIterator) Next() bool {
+	for iterator.next >= len(iterator.page) {
+		if iterator.done || iterator.err != nil {
+			return false
+		}
+
+		artifactsResponse, nextPageToken, err := iterator.fetch(iterator.ctx, iterator.options)
+		if err != nil {
+			iterator.err = err
+			return false
+		}
+		iterator.page = artifactsResponse.GetArtifacts()
+		iterator.next = 0
+		iterator.options.PageToken = nextPageToken
+		iterator.done = nextPageToken == ""
+	}
+
+	iterator.next++
+	return true
+}
+
+// Artifact returns the current artifact.
+func (iterator *ArtifactIterator) Artifact() *pb.ArtifactData {
+	if iterator.next == 0 || iterator.next > len(iterator.page) {
+		return nil
+	}
+	return iterator.page[iterator.next-1]
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (iterator *ArtifactIterator) Err() error {
+	return iterator.err
+}
+
+// listOperationOptions converts the options to MLMD's, nil if they are all
+// unset so that everything is listed.
+func (options ListOptions) listOperationOptions() *pb.ListOperationOptions {
+	if options == (ListOptions{}) {
+		return nil
+	}
+
+	field := pb.ListOperationOptions_OrderByField_ID
+	switch options.OrderBy {
+	case OrderByCreateTime:
+		field = pb.ListOperationOptions_OrderByField_CREATE_TIME
+	case OrderByLastUpdateTime:
+		field = pb.ListOperationOptions_OrderByField_LAST_UPDATE_TIME
+	}
+	isAsc := !options.Descending
+
+	// The page size is always sent so that the pages following the first one
+	// keep its size
+	pageSize := options.PageSize
+	if pageSize == 0 {
+		pageSize = pb.Default_ListOperationOptions_MaxResultSize
+	}
+
+	listOptions := &pb.ListOperationOptions{
+		MaxResultSize: &pageSize,
+		OrderByField:  &pb.ListOperationOptions_OrderByField{Field: field.Enum(), IsAsc: &isAsc},
+	}
+	if options.PageToken != "" {
+		listOptions.NextPageToken = &options.PageToken
+	}
+	return listOptions
+}
@@ -0,0 +1,20 @@
+package artifact_registry
+
+import "testing"
+
+func TestListOperationOptions(t *testing.T) {
+	if listOptions := (ListOptions{}).listOperationOptions(); listOptions != nil {
+		t.Errorf("listOperationOptions() = %v, want nil", listOptions)
+	}
+
+	// The next pages keep the default size of the first one
+	listOptions := ListOptions{PageToken: "token"}.listOperationOptions()
+	if listOptions.MaxResultSize == nil || listOptions.GetMaxResultSize() != 20 || listOptions.GetNextPageToken() != "token" {
+		t.Errorf("listOperationOptions() with a page token = %v", listOptions)
+	}
+
+	listOptions = ListOptions{PageSize: 5, OrderBy: OrderByCreateTime, Descending: true}.listOperationOptions()
+	if listOptions.GetMaxResultSize() != 5 || listOptions.GetOrderByField().GetIsAsc() || listOptions.NextPageToken != nil {
+		t.Errorf("listOperationOptions() = %v", listOptions)
+	}
+}
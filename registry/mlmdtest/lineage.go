@@ -0,0 +1,423 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Events, attributions, associations and parent contexts
+
+package mlmdtest
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+)
+
+func (s *Store) putEvent(event *pb.Event) error {
+	if _, ok := s.state.artifacts[event.GetArtifactId()]; !ok {
+		return status.Errorf(codes.InvalidArgument, "event refers to unknown artifact %d", event.GetArtifactId())
+	}
+	if _, ok := s.state.executions[event.GetExecutionId()]; !ok {
+		return status.Errorf(codes.InvalidArgument, "event refers to unknown execution %d", event.GetExecutionId())
+	}
+	if event.GetType() == pb.Event_UNKNOWN {
+		return status.Error(codes.InvalidArgument, "event type is required")
+	}
+	for _, other := range s.state.events {
+		if other.GetArtifactId() == event.GetArtifactId() && other.GetExecutionId() == event.GetExecutionId() && other.GetType() == event.GetType() {
+			return status.Errorf(codes.AlreadyExists, "event between artifact %d and execution %d already exists", event.GetArtifactId(), event.GetExecutionId())
+		}
+	}
+
+	stored := proto.Clone(event).(*pb.Event)
+	if stored.MillisecondsSinceEpoch == nil {
+		now := s.now()
+		stored.MillisecondsSinceEpoch = &now
+	}
+	s.state.events = append(s.state.events, stored)
+	return nil
+}
+
+func (s *Store) putAttribution(contextID int64, artifactID int64) error {
+	if _, ok := s.state.contexts[contextID]; !ok {
+		return status.Errorf(codes.InvalidArgument, "attribution refers to unknown context %d", contextID)
+	}
+	if _, ok := s.state.artifacts[artifactID]; !ok {
+		return status.Errorf(codes.InvalidArgument, "attribution refers to unknown artifact %d", artifactID)
+	}
+	for _, attribution := range s.state.attributions {
+		if attribution.GetContextId() == contextID && attribution.GetArtifactId() == artifactID {
+			return nil
+		}
+	}
+	s.state.attributions = append(s.state.attributions, &pb.Attribution{ContextId: &contextID, ArtifactId: &artifactID})
+	return nil
+}
+
+func (s *Store) putAssociation(contextID int64, executionID int64) error {
+	if _, ok := s.state.contexts[contextID]; !ok {
+		return status.Errorf(codes.InvalidArgument, "association refers to unknown context %d", contextID)
+	}
+	if _, ok := s.state.executions[executionID]; !ok {
+		return status.Errorf(codes.InvalidArgument, "association refers to unknown execution %d", executionID)
+	}
+	for _, association := range s.state.associations {
+		if association.GetContextId() == contextID && association.GetExecutionId() == executionID {
+			return nil
+		}
+	}
+	s.state.associations = append(s.state.associations, &pb.Association{ContextId: &contextID, ExecutionId: &executionID})
+	return nil
+}
+
+// PutEvents inserts events between existing artifacts and executions.
+func (s *Store) PutEvents(ctx context.Context, in *pb.PutEventsRequest, opts ...grpc.CallOption) (*pb.PutEventsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "PutEvents"); err != nil {
+		return nil, err
+	}
+
+	err := s.transaction(func() error {
+		for _, event := range in.GetEvents() {
+			if err := s.putEvent(event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.PutEventsResponse{}, nil
+}
+
+// PutExecution atomically inserts or updates an execution along with its
+// artifacts, events and contexts. Nothing is written if any part fails.
+func (s *Store) PutExecution(ctx context.Context, in *pb.PutExecutionRequest, opts ...grpc.CallOption) (*pb.PutExecutionResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "PutExecution"); err != nil {
+		return nil, err
+	}
+
+	response := &pb.PutExecutionResponse{}
+	err := s.transaction(func() error {
+		executionID, err := s.putExecution(in.GetExecution())
+		if err != nil {
+			return err
+		}
+		response.ExecutionId = &executionID
+
+		for _, pair := range in.GetArtifactEventPairs() {
+			artifactID := pair.GetEvent().GetArtifactId()
+			if pair.Artifact != nil {
+				if artifactID, err = s.putArtifact(pair.GetArtifact(), false); err != nil {
+					return err
+				}
+			}
+			response.ArtifactIds = append(response.ArtifactIds, artifactID)
+
+			if pair.Event == nil {
+				continue
+			}
+			event := proto.Clone(pair.GetEvent()).(*pb.Event)
+			if event.ArtifactId != nil && event.GetArtifactId() != artifactID {
+				return status.Errorf(codes.InvalidArgument, "event artifact_id %d does not match artifact %d", event.GetArtifactId(), artifactID)
+			}
+			if event.ExecutionId != nil && event.GetExecutionId() != executionID {
+				return status.Errorf(codes.InvalidArgument, "event execution_id %d does not match execution %d", event.GetExecutionId(), executionID)
+			}
+			event.ArtifactId = &artifactID
+			event.ExecutionId = &executionID
+			if err := s.putEvent(event); err != nil {
+				return err
+			}
+		}
+
+		for _, metadataContext := range in.GetContexts() {
+			contextID, err := s.putContext(metadataContext)
+			if status.Code(err) == codes.AlreadyExists && metadataContext.Id == nil && in.GetOptions().GetReuseContextIfAlreadyExist() {
+				contextID, err = s.findContext(metadataContext.GetTypeId(), metadataContext.GetName()).GetId(), nil
+			}
+			if err != nil {
+				return err
+			}
+			response.ContextIds = append(response.ContextIds, contextID)
+
+			if err := s.putAssociation(contextID, executionID); err != nil {
+				return err
+			}
+			for _, artifactID := range response.ArtifactIds {
+				if artifactID == 0 {
+					continue
+				}
+				if err := s.putAttribution(contextID, artifactID); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// PutAttributionsAndAssociations links artifacts and executions to contexts.
+// Existing links are left untouched.
+func (s *Store) PutAttributionsAndAssociations(ctx context.Context, in *pb.PutAttributionsAndAssociationsRequest, opts ...grpc.CallOption) (*pb.PutAttributionsAndAssociationsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "PutAttributionsAndAssociations"); err != nil {
+		return nil, err
+	}
+
+	err := s.transaction(func() error {
+		for _, attribution := range in.GetAttributions() {
+			if err := s.putAttribution(attribution.GetContextId(), attribution.GetArtifactId()); err != nil {
+				return err
+			}
+		}
+		for _, association := range in.GetAssociations() {
+			if err := s.putAssociation(association.GetContextId(), association.GetExecutionId()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.PutAttributionsAndAssociationsResponse{}, nil
+}
+
+// PutParentContexts links child contexts to their parents.
+func (s *Store) PutParentContexts(ctx context.Context, in *pb.PutParentContextsRequest, opts ...grpc.CallOption) (*pb.PutParentContextsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "PutParentContexts"); err != nil {
+		return nil, err
+	}
+
+	err := s.transaction(func() error {
+		for _, parentContext := range in.GetParentContexts() {
+			if _, ok := s.state.contexts[parentContext.GetChildId()]; !ok {
+				return status.Errorf(codes.InvalidArgument, "unknown child context %d", parentContext.GetChildId())
+			}
+			if _, ok := s.state.contexts[parentContext.GetParentId()]; !ok {
+				return status.Errorf(codes.InvalidArgument, "unknown parent context %d", parentContext.GetParentId())
+			}
+			for _, other := range s.state.parentContexts {
+				if other.GetChildId() == parentContext.GetChildId() && other.GetParentId() == parentContext.GetParentId() {
+					return status.Errorf(codes.AlreadyExists, "context %d is already a parent of %d", parentContext.GetParentId(), parentContext.GetChildId())
+				}
+			}
+			s.state.parentContexts = append(s.state.parentContexts, proto.Clone(parentContext).(*pb.ParentContext))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.PutParentContextsResponse{}, nil
+}
+
+// GetEventsByExecutionIDs returns the events of the requested executions.
+func (s *Store) GetEventsByExecutionIDs(ctx context.Context, in *pb.GetEventsByExecutionIDsRequest, opts ...grpc.CallOption) (*pb.GetEventsByExecutionIDsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetEventsByExecutionIDs"); err != nil {
+		return nil, err
+	}
+
+	executionIDs := idSet(in.GetExecutionIds())
+	response := &pb.GetEventsByExecutionIDsResponse{}
+	for _, event := range s.state.events {
+		if executionIDs[event.GetExecutionId()] {
+			response.Events = append(response.Events, proto.Clone(event).(*pb.Event))
+		}
+	}
+	return response, nil
+}
+
+// GetEventsByArtifactIDs returns the events of the requested artifacts.
+func (s *Store) GetEventsByArtifactIDs(ctx context.Context, in *pb.GetEventsByArtifactIDsRequest, opts ...grpc.CallOption) (*pb.GetEventsByArtifactIDsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetEventsByArtifactIDs"); err != nil {
+		return nil, err
+	}
+
+	artifactIDs := idSet(in.GetArtifactIds())
+	response := &pb.GetEventsByArtifactIDsResponse{}
+	for _, event := range s.state.events {
+		if artifactIDs[event.GetArtifactId()] {
+			response.Events = append(response.Events, proto.Clone(event).(*pb.Event))
+		}
+	}
+	return response, nil
+}
+
+// GetContextsByArtifact returns the contexts an artifact is attributed to.
+func (s *Store) GetContextsByArtifact(ctx context.Context, in *pb.GetContextsByArtifactRequest, opts ...grpc.CallOption) (*pb.GetContextsByArtifactResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetContextsByArtifact"); err != nil {
+		return nil, err
+	}
+
+	contextIDs := make(map[int64]bool)
+	for _, attribution := range s.state.attributions {
+		if attribution.GetArtifactId() == in.GetArtifactId() {
+			contextIDs[attribution.GetContextId()] = true
+		}
+	}
+	contexts, _, err := s.listContexts(nil, func(metadataContext *pb.Context) bool {
+		return contextIDs[metadataContext.GetId()]
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetContextsByArtifactResponse{Contexts: contexts}, nil
+}
+
+// GetContextsByExecution returns the contexts an execution is associated
+// with.
+func (s *Store) GetContextsByExecution(ctx context.Context, in *pb.GetContextsByExecutionRequest, opts ...grpc.CallOption) (*pb.GetContextsByExecutionResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetContextsByExecution"); err != nil {
+		return nil, err
+	}
+
+	contextIDs := make(map[int64]bool)
+	for _, association := range s.state.associations {
+		if association.GetExecutionId() == in.GetExecutionId() {
+			contextIDs[association.GetContextId()] = true
+		}
+	}
+	contexts, _, err := s.listContexts(nil, func(metadataContext *pb.Context) bool {
+		return contextIDs[metadataContext.GetId()]
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetContextsByExecutionResponse{Contexts: contexts}, nil
+}
+
+// GetParentContextsByContext returns the parents of a context.
+func (s *Store) GetParentContextsByContext(ctx context.Context, in *pb.GetParentContextsByContextRequest, opts ...grpc.CallOption) (*pb.GetParentContextsByContextResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetParentContextsByContext"); err != nil {
+		return nil, err
+	}
+
+	contextIDs := make(map[int64]bool)
+	for _, parentContext := range s.state.parentContexts {
+		if parentContext.GetChildId() == in.GetContextId() {
+			contextIDs[parentContext.GetParentId()] = true
+		}
+	}
+	contexts, _, err := s.listContexts(nil, func(metadataContext *pb.Context) bool {
+		return contextIDs[metadataContext.GetId()]
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetParentContextsByContextResponse{Contexts: contexts}, nil
+}
+
+// GetChildrenContextsByContext returns the children of a context.
+func (s *Store) GetChildrenContextsByContext(ctx context.Context, in *pb.GetChildrenContextsByContextRequest, opts ...grpc.CallOption) (*pb.GetChildrenContextsByContextResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetChildrenContextsByContext"); err != nil {
+		return nil, err
+	}
+
+	contextIDs := make(map[int64]bool)
+	for _, parentContext := range s.state.parentContexts {
+		if parentContext.GetParentId() == in.GetContextId() {
+			contextIDs[parentContext.GetChildId()] = true
+		}
+	}
+	contexts, _, err := s.listContexts(nil, func(metadataContext *pb.Context) bool {
+		return contextIDs[metadataContext.GetId()]
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetChildrenContextsByContextResponse{Contexts: contexts}, nil
+}
+
+// GetArtifactsByContext lists the artifacts attributed to a context
+// following the list options of the request.
+func (s *Store) GetArtifactsByContext(ctx context.Context, in *pb.GetArtifactsByContextRequest, opts ...grpc.CallOption) (*pb.GetArtifactsByContextResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetArtifactsByContext"); err != nil {
+		return nil, err
+	}
+
+	artifactIDs := make(map[int64]bool)
+	for _, attribution := range s.state.attributions {
+		if attribution.GetContextId() == in.GetContextId() {
+			artifactIDs[attribution.GetArtifactId()] = true
+		}
+	}
+	artifacts, nextPageToken, err := s.listArtifacts(in.GetOptions(), func(artifact *pb.Artifact) bool {
+		return artifactIDs[artifact.GetId()]
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetArtifactsByContextResponse{Artifacts: artifacts, NextPageToken: optionalString(nextPageToken)}, nil
+}
+
+// GetExecutionsByContext lists the executions associated with a context
+// following the list options of the request.
+func (s *Store) GetExecutionsByContext(ctx context.Context, in *pb.GetExecutionsByContextRequest, opts ...grpc.CallOption) (*pb.GetExecutionsByContextResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetExecutionsByContext"); err != nil {
+		return nil, err
+	}
+
+	executionIDs := make(map[int64]bool)
+	for _, association := range s.state.associations {
+		if association.GetContextId() == in.GetContextId() {
+			executionIDs[association.GetExecutionId()] = true
+		}
+	}
+	executions, nextPageToken, err := s.listExecutions(in.GetOptions(), func(execution *pb.Execution) bool {
+		return executionIDs[execution.GetId()]
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetExecutionsByContextResponse{Executions: executions, NextPageToken: optionalString(nextPageToken)}, nil
+}
+
+func idSet(ids []int64) map[int64]bool {
+	set := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
@@ -0,0 +1,563 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Artifacts, executions and contexts
+
+package mlmdtest
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+)
+
+func (s *Store) putArtifact(artifact *pb.Artifact, abortIfUpdated bool) (int64, error) {
+	artifactType, ok := s.state.artifactTypes[artifact.GetTypeId()]
+	if !ok {
+		return 0, status.Errorf(codes.InvalidArgument, "artifact type %d does not exist", artifact.GetTypeId())
+	}
+	if err := checkProperties("artifact", artifactType.GetProperties(), artifact.GetProperties()); err != nil {
+		return 0, err
+	}
+
+	stored := cloneArtifact(artifact)
+	stored.Type = artifactType.Name
+	if stored.State == nil {
+		stored.State = pb.Artifact_UNKNOWN.Enum()
+	}
+	now := s.now()
+	stored.LastUpdateTimeSinceEpoch = &now
+
+	if artifact.Id != nil {
+		existing, ok := s.state.artifacts[artifact.GetId()]
+		if !ok {
+			return 0, status.Errorf(codes.NotFound, "artifact %d does not exist", artifact.GetId())
+		}
+		if existing.GetTypeId() != artifact.GetTypeId() {
+			return 0, status.Errorf(codes.InvalidArgument, "artifact %d can not change its type", artifact.GetId())
+		}
+		if abortIfUpdated && existing.GetLastUpdateTimeSinceEpoch() != artifact.GetLastUpdateTimeSinceEpoch() {
+			return 0, status.Errorf(codes.FailedPrecondition, "artifact %d was updated concurrently", artifact.GetId())
+		}
+		stored.CreateTimeSinceEpoch = existing.CreateTimeSinceEpoch
+	} else {
+		id := s.state.nextArtifactID
+		stored.Id = &id
+		stored.CreateTimeSinceEpoch = &now
+	}
+
+	if stored.GetName() != "" {
+		for id, other := range s.state.artifacts {
+			if id != stored.GetId() && other.GetTypeId() == stored.GetTypeId() && other.GetName() == stored.GetName() {
+				return 0, status.Errorf(codes.AlreadyExists, "artifact named %s already exists", stored.GetName())
+			}
+		}
+	}
+
+	if artifact.Id == nil {
+		s.state.nextArtifactID++
+	}
+	s.state.artifacts[stored.GetId()] = stored
+	return stored.GetId(), nil
+}
+
+func (s *Store) putExecution(execution *pb.Execution) (int64, error) {
+	executionType, ok := s.state.executionTypes[execution.GetTypeId()]
+	if !ok {
+		return 0, status.Errorf(codes.InvalidArgument, "execution type %d does not exist", execution.GetTypeId())
+	}
+	if err := checkProperties("execution", executionType.GetProperties(), execution.GetProperties()); err != nil {
+		return 0, err
+	}
+
+	stored := cloneExecution(execution)
+	stored.Type = executionType.Name
+	if stored.LastKnownState == nil {
+		stored.LastKnownState = pb.Execution_UNKNOWN.Enum()
+	}
+	now := s.now()
+	stored.LastUpdateTimeSinceEpoch = &now
+
+	if execution.Id != nil {
+		existing, ok := s.state.executions[execution.GetId()]
+		if !ok {
+			return 0, status.Errorf(codes.NotFound, "execution %d does not exist", execution.GetId())
+		}
+		if existing.GetTypeId() != execution.GetTypeId() {
+			return 0, status.Errorf(codes.InvalidArgument, "execution %d can not change its type", execution.GetId())
+		}
+		stored.CreateTimeSinceEpoch = existing.CreateTimeSinceEpoch
+	} else {
+		id := s.state.nextExecutionID
+		stored.Id = &id
+		stored.CreateTimeSinceEpoch = &now
+	}
+
+	if stored.GetName() != "" {
+		for id, other := range s.state.executions {
+			if id != stored.GetId() && other.GetTypeId() == stored.GetTypeId() && other.GetName() == stored.GetName() {
+				return 0, status.Errorf(codes.AlreadyExists, "execution named %s already exists", stored.GetName())
+			}
+		}
+	}
+
+	if execution.Id == nil {
+		s.state.nextExecutionID++
+	}
+	s.state.executions[stored.GetId()] = stored
+	return stored.GetId(), nil
+}
+
+func (s *Store) putContext(ctx *pb.Context) (int64, error) {
+	contextType, ok := s.state.contextTypes[ctx.GetTypeId()]
+	if !ok {
+		return 0, status.Errorf(codes.InvalidArgument, "context type %d does not exist", ctx.GetTypeId())
+	}
+	if ctx.GetName() == "" {
+		return 0, status.Error(codes.InvalidArgument, "context name is required")
+	}
+	if err := checkProperties("context", contextType.GetProperties(), ctx.GetProperties()); err != nil {
+		return 0, err
+	}
+
+	stored := cloneContext(ctx)
+	stored.Type = contextType.Name
+	now := s.now()
+	stored.LastUpdateTimeSinceEpoch = &now
+
+	if ctx.Id != nil {
+		existing, ok := s.state.contexts[ctx.GetId()]
+		if !ok {
+			return 0, status.Errorf(codes.NotFound, "context %d does not exist", ctx.GetId())
+		}
+		if existing.GetTypeId() != ctx.GetTypeId() {
+			return 0, status.Errorf(codes.InvalidArgument, "context %d can not change its type", ctx.GetId())
+		}
+		stored.CreateTimeSinceEpoch = existing.CreateTimeSinceEpoch
+	} else {
+		id := s.state.nextContextID
+		stored.Id = &id
+		stored.CreateTimeSinceEpoch = &now
+	}
+
+	if other := s.findContext(stored.GetTypeId(), stored.GetName()); other != nil && other.GetId() != stored.GetId() {
+		return 0, status.Errorf(codes.AlreadyExists, "context named %s already exists", stored.GetName())
+	}
+
+	if ctx.Id == nil {
+		s.state.nextContextID++
+	}
+	s.state.contexts[stored.GetId()] = stored
+	return stored.GetId(), nil
+}
+
+func (s *Store) findContext(typeID int64, name string) *pb.Context {
+	for _, ctx := range s.state.contexts {
+		if ctx.GetTypeId() == typeID && ctx.GetName() == name {
+			return ctx
+		}
+	}
+	return nil
+}
+
+// PutArtifacts inserts artifacts without an ID and updates the others.
+func (s *Store) PutArtifacts(ctx context.Context, in *pb.PutArtifactsRequest, opts ...grpc.CallOption) (*pb.PutArtifactsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "PutArtifacts"); err != nil {
+		return nil, err
+	}
+
+	response := &pb.PutArtifactsResponse{}
+	err := s.transaction(func() error {
+		for _, artifact := range in.GetArtifacts() {
+			id, err := s.putArtifact(artifact, in.GetOptions().GetAbortIfLatestUpdatedTimeChanged())
+			if err != nil {
+				return err
+			}
+			response.ArtifactIds = append(response.ArtifactIds, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// PutExecutions inserts executions without an ID and updates the others.
+func (s *Store) PutExecutions(ctx context.Context, in *pb.PutExecutionsRequest, opts ...grpc.CallOption) (*pb.PutExecutionsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "PutExecutions"); err != nil {
+		return nil, err
+	}
+
+	response := &pb.PutExecutionsResponse{}
+	err := s.transaction(func() error {
+		for _, execution := range in.GetExecutions() {
+			id, err := s.putExecution(execution)
+			if err != nil {
+				return err
+			}
+			response.ExecutionIds = append(response.ExecutionIds, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// PutContexts inserts contexts without an ID and updates the others.
+func (s *Store) PutContexts(ctx context.Context, in *pb.PutContextsRequest, opts ...grpc.CallOption) (*pb.PutContextsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "PutContexts"); err != nil {
+		return nil, err
+	}
+
+	response := &pb.PutContextsResponse{}
+	err := s.transaction(func() error {
+		for _, metadataContext := range in.GetContexts() {
+			id, err := s.putContext(metadataContext)
+			if err != nil {
+				return err
+			}
+			response.ContextIds = append(response.ContextIds, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetArtifacts lists artifacts following the list options of the request.
+func (s *Store) GetArtifacts(ctx context.Context, in *pb.GetArtifactsRequest, opts ...grpc.CallOption) (*pb.GetArtifactsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetArtifacts"); err != nil {
+		return nil, err
+	}
+
+	artifacts, nextPageToken, err := s.listArtifacts(in.GetOptions(), func(*pb.Artifact) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetArtifactsResponse{Artifacts: artifacts, NextPageToken: optionalString(nextPageToken)}, nil
+}
+
+// GetExecutions lists executions following the list options of the request.
+func (s *Store) GetExecutions(ctx context.Context, in *pb.GetExecutionsRequest, opts ...grpc.CallOption) (*pb.GetExecutionsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetExecutions"); err != nil {
+		return nil, err
+	}
+
+	executions, nextPageToken, err := s.listExecutions(in.GetOptions(), func(*pb.Execution) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetExecutionsResponse{Executions: executions, NextPageToken: optionalString(nextPageToken)}, nil
+}
+
+// GetContexts lists contexts following the list options of the request.
+func (s *Store) GetContexts(ctx context.Context, in *pb.GetContextsRequest, opts ...grpc.CallOption) (*pb.GetContextsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetContexts"); err != nil {
+		return nil, err
+	}
+
+	contexts, nextPageToken, err := s.listContexts(in.GetOptions(), func(*pb.Context) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetContextsResponse{Contexts: contexts, NextPageToken: optionalString(nextPageToken)}, nil
+}
+
+// GetArtifactsByID returns the artifacts with the requested IDs, unknown IDs
+// are ignored.
+func (s *Store) GetArtifactsByID(ctx context.Context, in *pb.GetArtifactsByIDRequest, opts ...grpc.CallOption) (*pb.GetArtifactsByIDResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetArtifactsByID"); err != nil {
+		return nil, err
+	}
+
+	response := &pb.GetArtifactsByIDResponse{}
+	for _, id := range in.GetArtifactIds() {
+		if artifact, ok := s.state.artifacts[id]; ok {
+			response.Artifacts = append(response.Artifacts, cloneArtifact(artifact))
+		}
+	}
+	return response, nil
+}
+
+// GetExecutionsByID returns the executions with the requested IDs, unknown
+// IDs are ignored.
+func (s *Store) GetExecutionsByID(ctx context.Context, in *pb.GetExecutionsByIDRequest, opts ...grpc.CallOption) (*pb.GetExecutionsByIDResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetExecutionsByID"); err != nil {
+		return nil, err
+	}
+
+	response := &pb.GetExecutionsByIDResponse{}
+	for _, id := range in.GetExecutionIds() {
+		if execution, ok := s.state.executions[id]; ok {
+			response.Executions = append(response.Executions, cloneExecution(execution))
+		}
+	}
+	return response, nil
+}
+
+// GetContextsByID returns the contexts with the requested IDs, unknown IDs
+// are ignored.
+func (s *Store) GetContextsByID(ctx context.Context, in *pb.GetContextsByIDRequest, opts ...grpc.CallOption) (*pb.GetContextsByIDResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetContextsByID"); err != nil {
+		return nil, err
+	}
+
+	response := &pb.GetContextsByIDResponse{}
+	for _, id := range in.GetContextIds() {
+		if metadataContext, ok := s.state.contexts[id]; ok {
+			response.Contexts = append(response.Contexts, cloneContext(metadataContext))
+		}
+	}
+	return response, nil
+}
+
+// GetArtifactsByType returns every artifact of the requested type.
+func (s *Store) GetArtifactsByType(ctx context.Context, in *pb.GetArtifactsByTypeRequest, opts ...grpc.CallOption) (*pb.GetArtifactsByTypeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetArtifactsByType"); err != nil {
+		return nil, err
+	}
+
+	artifacts, _, err := s.listArtifacts(nil, func(artifact *pb.Artifact) bool {
+		return artifact.GetType() == in.GetTypeName()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetArtifactsByTypeResponse{Artifacts: artifacts}, nil
+}
+
+// GetExecutionsByType returns every execution of the requested type.
+func (s *Store) GetExecutionsByType(ctx context.Context, in *pb.GetExecutionsByTypeRequest, opts ...grpc.CallOption) (*pb.GetExecutionsByTypeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetExecutionsByType"); err != nil {
+		return nil, err
+	}
+
+	executions, _, err := s.listExecutions(nil, func(execution *pb.Execution) bool {
+		return execution.GetType() == in.GetTypeName()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetExecutionsByTypeResponse{Executions: executions}, nil
+}
+
+// GetContextsByType lists the contexts of the requested type following the
+// list options of the request.
+func (s *Store) GetContextsByType(ctx context.Context, in *pb.GetContextsByTypeRequest, opts ...grpc.CallOption) (*pb.GetContextsByTypeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetContextsByType"); err != nil {
+		return nil, err
+	}
+
+	contexts, nextPageToken, err := s.listContexts(in.GetOptions(), func(metadataContext *pb.Context) bool {
+		return metadataContext.GetType() == in.GetTypeName()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetContextsByTypeResponse{Contexts: contexts, NextPageToken: optionalString(nextPageToken)}, nil
+}
+
+// GetArtifactByTypeAndName returns the artifact of the requested type and
+// name, or an empty response if there is none.
+func (s *Store) GetArtifactByTypeAndName(ctx context.Context, in *pb.GetArtifactByTypeAndNameRequest, opts ...grpc.CallOption) (*pb.GetArtifactByTypeAndNameResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetArtifactByTypeAndName"); err != nil {
+		return nil, err
+	}
+
+	for _, artifact := range s.state.artifacts {
+		if artifact.GetType() == in.GetTypeName() && artifact.GetName() == in.GetArtifactName() {
+			return &pb.GetArtifactByTypeAndNameResponse{Artifact: cloneArtifact(artifact)}, nil
+		}
+	}
+	return &pb.GetArtifactByTypeAndNameResponse{}, nil
+}
+
+// GetExecutionByTypeAndName returns the execution of the requested type and
+// name, or an empty response if there is none.
+func (s *Store) GetExecutionByTypeAndName(ctx context.Context, in *pb.GetExecutionByTypeAndNameRequest, opts ...grpc.CallOption) (*pb.GetExecutionByTypeAndNameResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetExecutionByTypeAndName"); err != nil {
+		return nil, err
+	}
+
+	for _, execution := range s.state.executions {
+		if execution.GetType() == in.GetTypeName() && execution.GetName() == in.GetExecutionName() {
+			return &pb.GetExecutionByTypeAndNameResponse{Execution: cloneExecution(execution)}, nil
+		}
+	}
+	return &pb.GetExecutionByTypeAndNameResponse{}, nil
+}
+
+// GetContextByTypeAndName returns the context of the requested type and
+// name, or an empty response if there is none.
+func (s *Store) GetContextByTypeAndName(ctx context.Context, in *pb.GetContextByTypeAndNameRequest, opts ...grpc.CallOption) (*pb.GetContextByTypeAndNameResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetContextByTypeAndName"); err != nil {
+		return nil, err
+	}
+
+	contextType := s.findContextType(in.GetTypeName())
+	if contextType == nil {
+		return &pb.GetContextByTypeAndNameResponse{}, nil
+	}
+	if metadataContext := s.findContext(contextType.GetId(), in.GetContextName()); metadataContext != nil {
+		return &pb.GetContextByTypeAndNameResponse{Context: cloneContext(metadataContext)}, nil
+	}
+	return &pb.GetContextByTypeAndNameResponse{}, nil
+}
+
+// GetArtifactsByURI returns the artifacts with any of the requested URIs.
+func (s *Store) GetArtifactsByURI(ctx context.Context, in *pb.GetArtifactsByURIRequest, opts ...grpc.CallOption) (*pb.GetArtifactsByURIResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetArtifactsByURI"); err != nil {
+		return nil, err
+	}
+
+	uris := make(map[string]bool)
+	for _, uri := range in.GetUris() {
+		uris[uri] = true
+	}
+	artifacts, _, err := s.listArtifacts(nil, func(artifact *pb.Artifact) bool {
+		return uris[artifact.GetUri()]
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetArtifactsByURIResponse{Artifacts: artifacts}, nil
+}
+
+func (s *Store) listArtifacts(options *pb.ListOperationOptions, match func(*pb.Artifact) bool) ([]*pb.Artifact, string, error) {
+	var candidates []*pb.Artifact
+	var items []listItem
+	for _, artifact := range s.state.artifacts {
+		if !match(artifact) {
+			continue
+		}
+		items = append(items, listItem{
+			index:      len(candidates),
+			id:         artifact.GetId(),
+			createTime: artifact.GetCreateTimeSinceEpoch(),
+			updateTime: artifact.GetLastUpdateTimeSinceEpoch(),
+		})
+		candidates = append(candidates, artifact)
+	}
+
+	indexes, nextPageToken, err := paginate(options, items)
+	if err != nil {
+		return nil, "", err
+	}
+	var artifacts []*pb.Artifact
+	for _, index := range indexes {
+		artifacts = append(artifacts, cloneArtifact(candidates[index]))
+	}
+	return artifacts, nextPageToken, nil
+}
+
+func (s *Store) listExecutions(options *pb.ListOperationOptions, match func(*pb.Execution) bool) ([]*pb.Execution, string, error) {
+	var candidates []*pb.Execution
+	var items []listItem
+	for _, execution := range s.state.executions {
+		if !match(execution) {
+			continue
+		}
+		items = append(items, listItem{
+			index:      len(candidates),
+			id:         execution.GetId(),
+			createTime: execution.GetCreateTimeSinceEpoch(),
+			updateTime: execution.GetLastUpdateTimeSinceEpoch(),
+		})
+		candidates = append(candidates, execution)
+	}
+
+	indexes, nextPageToken, err := paginate(options, items)
+	if err != nil {
+		return nil, "", err
+	}
+	var executions []*pb.Execution
+	for _, index := range indexes {
+		executions = append(executions, cloneExecution(candidates[index]))
+	}
+	return executions, nextPageToken, nil
+}
+
+func (s *Store) listContexts(options *pb.ListOperationOptions, match func(*pb.Context) bool) ([]*pb.Context, string, error) {
+	var candidates []*pb.Context
+	var items []listItem
+	for _, metadataContext := range s.state.contexts {
+		if !match(metadataContext) {
+			continue
+		}
+		items = append(items, listItem{
+			index:      len(candidates),
+			id:         metadataContext.GetId(),
+			createTime: metadataContext.GetCreateTimeSinceEpoch(),
+			updateTime: metadataContext.GetLastUpdateTimeSinceEpoch(),
+		})
+		candidates = append(candidates, metadataContext)
+	}
+
+	indexes, nextPageToken, err := paginate(options, items)
+	if err != nil {
+		return nil, "", err
+	}
+	var contexts []*pb.Context
+	for _, index := range indexes {
+		contexts = append(contexts, cloneContext(candidates[index]))
+	}
+	return contexts, nextPageToken, nil
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,292 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Helpers to seed the store with Kubeflow records
+
+package mlmdtest
+
+import (
+	"fmt"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+)
+
+// Kubeflow type names registered by SeedKubeflowTypes
+const (
+	WorkspaceType = "kubeflow.org/alpha/workspace"
+	ModelType     = "kubeflow.org/alpha/model"
+	DatasetType   = "kubeflow.org/alpha/data_set"
+	MetricsType   = "kubeflow.org/alpha/metrics"
+	ExecutionType = "kubeflow.org/alpha/execution"
+)
+
+// Artifact describes an artifact created by SeedArtifact following the
+// Kubeflow conventions.
+type Artifact struct {
+	// Artifact type name, e.g. ModelType
+	Type    string
+	Name    string
+	Version string
+	URI     string
+	// Workspace the artifact is attributed to, created if missing
+	Workspace string
+	RunID     string
+	State     pb.Artifact_State
+
+	// Additional properties, they must be defined by the type
+	Properties       map[string]*pb.Value
+	CustomProperties map[string]*pb.Value
+}
+
+// Execution describes an execution created by SeedExecution following the
+// Kubeflow conventions.
+type Execution struct {
+	// Execution type name, ExecutionType if empty
+	Type string
+	Name string
+	// Workspace the execution is associated with, created if missing
+	Workspace string
+	RunID     string
+	State     pb.Execution_State
+
+	// IDs of the artifacts declared as inputs and outputs
+	Inputs  []int64
+	Outputs []int64
+}
+
+// SeedKubeflowTypes registers the workspace context type and the model,
+// dataset, metrics and execution types with the properties Kubeflow defines.
+func (s *Store) SeedKubeflowTypes() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stringProperties := func(names ...string) map[string]pb.PropertyType {
+		properties := make(map[string]pb.PropertyType)
+		for _, name := range names {
+			properties[name] = pb.PropertyType_STRING
+		}
+		return properties
+	}
+
+	must(s.putContextType(&pb.ContextType{
+		Name:       stringPtr(WorkspaceType),
+		Properties: stringProperties("name", "description", "owner", "create_time", "labels"),
+	}, true, true))
+	must(s.putArtifactType(&pb.ArtifactType{
+		Name: stringPtr(ModelType),
+		Properties: stringProperties("name", "description", "owner", "version", "create_time",
+			"model_type", "training_framework", "hyperparameters", "labels", "kwargs"),
+	}, true, true))
+	must(s.putArtifactType(&pb.ArtifactType{
+		Name:       stringPtr(DatasetType),
+		Properties: stringProperties("name", "description", "owner", "version", "create_time", "query", "labels"),
+	}, true, true))
+	must(s.putArtifactType(&pb.ArtifactType{
+		Name: stringPtr(MetricsType),
+		Properties: stringProperties("name", "description", "owner", "create_time", "data_set_id", "model_id",
+			"metrics_type", "values", "labels"),
+	}, true, true))
+	must(s.putExecutionType(&pb.ExecutionType{
+		Name:       stringPtr(ExecutionType),
+		Properties: stringProperties("name", "description", "create_time", "pipeline_name", "run_id", "workspace"),
+	}, true, true))
+}
+
+// SeedWorkspace returns the ID of the named workspace, creating it and its
+// context type if needed.
+func (s *Store) SeedWorkspace(name string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.seedWorkspace(name)
+}
+
+func (s *Store) seedWorkspace(name string) int64 {
+	contextType := s.findContextType(WorkspaceType)
+	if contextType == nil {
+		must(s.putContextType(&pb.ContextType{
+			Name:       stringPtr(WorkspaceType),
+			Properties: map[string]pb.PropertyType{"name": pb.PropertyType_STRING},
+		}, true, true))
+		contextType = s.findContextType(WorkspaceType)
+	}
+	if workspace := s.findContext(contextType.GetId(), name); workspace != nil {
+		return workspace.GetId()
+	}
+
+	workspace := &pb.Context{
+		TypeId:           contextType.Id,
+		Name:             &name,
+		Properties:       map[string]*pb.Value{},
+		CustomProperties: map[string]*pb.Value{},
+	}
+	setProperty(workspace.Properties, workspace.CustomProperties, contextType.GetProperties(), "name", name)
+	return must(s.putContext(workspace))
+}
+
+// SeedArtifact creates an artifact and returns its ID. Name and version are
+// stored as properties when the type defines them, otherwise as custom
+// properties. It panics if the artifact type does not exist.
+func (s *Store) SeedArtifact(artifact Artifact) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	artifactType := s.findArtifactType(artifact.Type)
+	if artifactType == nil {
+		panic(fmt.Sprintf("mlmdtest: artifact type %s does not exist", artifact.Type))
+	}
+
+	record := &pb.Artifact{
+		TypeId:           artifactType.Id,
+		Uri:              &artifact.URI,
+		State:            artifact.State.Enum(),
+		Properties:       map[string]*pb.Value{},
+		CustomProperties: map[string]*pb.Value{},
+	}
+	for key, value := range artifact.Properties {
+		record.Properties[key] = value
+	}
+	for key, value := range artifact.CustomProperties {
+		record.CustomProperties[key] = value
+	}
+	if artifact.Name != "" {
+		setProperty(record.Properties, record.CustomProperties, artifactType.GetProperties(), "name", artifact.Name)
+	}
+	if artifact.Version != "" {
+		setProperty(record.Properties, record.CustomProperties, artifactType.GetProperties(), "version", artifact.Version)
+	}
+	if artifact.Workspace != "" {
+		record.CustomProperties["__kf_workspace__"] = StringValue(artifact.Workspace)
+	}
+	if artifact.RunID != "" {
+		record.CustomProperties["__kf_run__"] = StringValue(artifact.RunID)
+	}
+
+	var id int64
+	err := s.transaction(func() error {
+		var err error
+		if id, err = s.putArtifact(record, false); err != nil {
+			return err
+		}
+		if artifact.Workspace != "" {
+			return s.putAttribution(s.seedWorkspace(artifact.Workspace), id)
+		}
+		return nil
+	})
+	if err != nil {
+		panic(fmt.Sprintf("mlmdtest: %v", err))
+	}
+	return id
+}
+
+// SeedExecution creates an execution with DECLARED_INPUT and DECLARED_OUTPUT
+// events to its inputs and outputs and returns its ID. It panics if the
+// execution type or any of the artifacts does not exist.
+func (s *Store) SeedExecution(execution Execution) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	typeName := execution.Type
+	if typeName == "" {
+		typeName = ExecutionType
+	}
+	executionType := s.findExecutionType(typeName)
+	if executionType == nil {
+		panic(fmt.Sprintf("mlmdtest: execution type %s does not exist", typeName))
+	}
+
+	record := &pb.Execution{
+		TypeId:           executionType.Id,
+		LastKnownState:   execution.State.Enum(),
+		Properties:       map[string]*pb.Value{},
+		CustomProperties: map[string]*pb.Value{},
+	}
+	if execution.Name != "" {
+		setProperty(record.Properties, record.CustomProperties, executionType.GetProperties(), "name", execution.Name)
+	}
+	if execution.RunID != "" {
+		setProperty(record.Properties, record.CustomProperties, executionType.GetProperties(), "run_id", execution.RunID)
+		record.CustomProperties["__kf_run__"] = StringValue(execution.RunID)
+	}
+	if execution.Workspace != "" {
+		record.CustomProperties["__kf_workspace__"] = StringValue(execution.Workspace)
+	}
+
+	var id int64
+	err := s.transaction(func() error {
+		var err error
+		if id, err = s.putExecution(record); err != nil {
+			return err
+		}
+		for _, artifactID := range execution.Inputs {
+			event := &pb.Event{ArtifactId: int64Ptr(artifactID), ExecutionId: &id, Type: pb.Event_DECLARED_INPUT.Enum()}
+			if err := s.putEvent(event); err != nil {
+				return err
+			}
+		}
+		for _, artifactID := range execution.Outputs {
+			event := &pb.Event{ArtifactId: int64Ptr(artifactID), ExecutionId: &id, Type: pb.Event_DECLARED_OUTPUT.Enum()}
+			if err := s.putEvent(event); err != nil {
+				return err
+			}
+		}
+		if execution.Workspace != "" {
+			return s.putAssociation(s.seedWorkspace(execution.Workspace), id)
+		}
+		return nil
+	})
+	if err != nil {
+		panic(fmt.Sprintf("mlmdtest: %v", err))
+	}
+	return id
+}
+
+// StringValue returns an MLMD string value.
+func StringValue(value string) *pb.Value {
+	return &pb.Value{Value: &pb.Value_StringValue{StringValue: value}}
+}
+
+// IntValue returns an MLMD int value.
+func IntValue(value int64) *pb.Value {
+	return &pb.Value{Value: &pb.Value_IntValue{IntValue: value}}
+}
+
+// DoubleValue returns an MLMD double value.
+func DoubleValue(value float64) *pb.Value {
+	return &pb.Value{Value: &pb.Value_DoubleValue{DoubleValue: value}}
+}
+
+// setProperty stores a string in properties if the schema defines it,
+// otherwise in custom properties.
+func setProperty(properties, customProperties map[string]*pb.Value, schema map[string]pb.PropertyType, key string, value string) {
+	if schema[key] == pb.PropertyType_STRING {
+		properties[key] = StringValue(value)
+		return
+	}
+	customProperties[key] = StringValue(value)
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func int64Ptr(value int64) *int64 {
+	return &value
+}
+
+func must(id int64, err error) int64 {
+	if err != nil {
+		panic(fmt.Sprintf("mlmdtest: %v", err))
+	}
+	return id
+}
@@ -0,0 +1,352 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Package mlmdtest provides an in-memory MLMD to test code built on the
+// artifact registry without a running MLMD server.
+//
+// Store implements pb.MetadataStoreServiceClient, inject it in an
+// MLArtifactStore with registry.WithClient:
+//
+//	mlmd := mlmdtest.NewStore()
+//	mlmd.SeedKubeflowTypes()
+//	mlmd.SeedArtifact(mlmdtest.Artifact{Type: mlmdtest.ModelType, Name: "MNIST", Workspace: "workspace_1"})
+//
+//	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+package mlmdtest
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+)
+
+// Store is an in-memory implementation of pb.MetadataStoreServiceClient
+// which follows the semantics of the MLMD gRPC server closely enough to test
+// against. It is safe for concurrent use.
+type Store struct {
+	mu       sync.Mutex
+	state    *state
+	lastTime int64
+	calls    map[string]int
+	errors   map[string]error
+}
+
+var _ pb.MetadataStoreServiceClient = (*Store)(nil)
+
+// state holds all the records of the store. Records are never modified in
+// place, updates replace them, so a shallow copy is enough to roll back a
+// failed write.
+type state struct {
+	nextTypeID      int64
+	nextArtifactID  int64
+	nextExecutionID int64
+	nextContextID   int64
+
+	artifactTypes  map[int64]*pb.ArtifactType
+	executionTypes map[int64]*pb.ExecutionType
+	contextTypes   map[int64]*pb.ContextType
+
+	artifacts  map[int64]*pb.Artifact
+	executions map[int64]*pb.Execution
+	contexts   map[int64]*pb.Context
+
+	events         []*pb.Event
+	attributions   []*pb.Attribution
+	associations   []*pb.Association
+	parentContexts []*pb.ParentContext
+}
+
+// NewStore returns an empty Store.
+func NewStore() *Store {
+	return &Store{
+		state: &state{
+			nextTypeID:      1,
+			nextArtifactID:  1,
+			nextExecutionID: 1,
+			nextContextID:   1,
+			artifactTypes:   make(map[int64]*pb.ArtifactType),
+			executionTypes:  make(map[int64]*pb.ExecutionType),
+			contextTypes:    make(map[int64]*pb.ContextType),
+			artifacts:       make(map[int64]*pb.Artifact),
+			executions:      make(map[int64]*pb.Execution),
+			contexts:        make(map[int64]*pb.Context),
+		},
+		calls:  make(map[string]int),
+		errors: make(map[string]error),
+	}
+}
+
+// SetError makes every following call to method, e.g. "PutArtifacts", fail
+// with err. A nil err clears it.
+func (s *Store) SetError(method string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err == nil {
+		delete(s.errors, method)
+		return
+	}
+	s.errors[method] = err
+}
+
+// Calls returns the number of calls made to method, e.g. "GetArtifactTypes".
+func (s *Store) Calls(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.calls[method]
+}
+
+// ResetCalls resets the call counters returned by Calls.
+func (s *Store) ResetCalls() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls = make(map[string]int)
+}
+
+// call records a call to method and returns the error it should fail with.
+// It must be called with the lock held.
+func (s *Store) call(ctx context.Context, method string) error {
+	s.calls[method]++
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return s.errors[method]
+}
+
+// transaction runs fn and rolls back every change it made if it fails. It
+// must be called with the lock held.
+func (s *Store) transaction(fn func() error) error {
+	backup := s.state.copy()
+	if err := fn(); err != nil {
+		s.state = backup
+		return err
+	}
+	return nil
+}
+
+// now returns the current time in milliseconds since epoch, strictly
+// increasing between calls so that records are ordered by creation.
+func (s *Store) now() int64 {
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	if now <= s.lastTime {
+		now = s.lastTime + 1
+	}
+	s.lastTime = now
+	return now
+}
+
+func (st *state) copy() *state {
+	backup := *st
+	backup.artifactTypes = make(map[int64]*pb.ArtifactType, len(st.artifactTypes))
+	for id, artifactType := range st.artifactTypes {
+		backup.artifactTypes[id] = artifactType
+	}
+	backup.executionTypes = make(map[int64]*pb.ExecutionType, len(st.executionTypes))
+	for id, executionType := range st.executionTypes {
+		backup.executionTypes[id] = executionType
+	}
+	backup.contextTypes = make(map[int64]*pb.ContextType, len(st.contextTypes))
+	for id, contextType := range st.contextTypes {
+		backup.contextTypes[id] = contextType
+	}
+	backup.artifacts = make(map[int64]*pb.Artifact, len(st.artifacts))
+	for id, artifact := range st.artifacts {
+		backup.artifacts[id] = artifact
+	}
+	backup.executions = make(map[int64]*pb.Execution, len(st.executions))
+	for id, execution := range st.executions {
+		backup.executions[id] = execution
+	}
+	backup.contexts = make(map[int64]*pb.Context, len(st.contexts))
+	for id, ctx := range st.contexts {
+		backup.contexts[id] = ctx
+	}
+	backup.events = append([]*pb.Event(nil), st.events...)
+	backup.attributions = append([]*pb.Attribution(nil), st.attributions...)
+	backup.associations = append([]*pb.Association(nil), st.associations...)
+	backup.parentContexts = append([]*pb.ParentContext(nil), st.parentContexts...)
+	return &backup
+}
+
+// checkProperties validates properties against the schema of their type.
+func checkProperties(kind string, schema map[string]pb.PropertyType, properties map[string]*pb.Value) error {
+	for name, value := range properties {
+		propertyType, ok := schema[name]
+		if !ok {
+			return status.Errorf(codes.InvalidArgument, "%s property %s is not defined in its type", kind, name)
+		}
+		if valueType(value) != propertyType {
+			return status.Errorf(codes.InvalidArgument, "%s property %s must be of type %s", kind, name, propertyType)
+		}
+	}
+	return nil
+}
+
+func valueType(value *pb.Value) pb.PropertyType {
+	switch value.GetValue().(type) {
+	case *pb.Value_IntValue:
+		return pb.PropertyType_INT
+	case *pb.Value_DoubleValue:
+		return pb.PropertyType_DOUBLE
+	case *pb.Value_StringValue:
+		return pb.PropertyType_STRING
+	case *pb.Value_StructValue:
+		return pb.PropertyType_STRUCT
+	}
+	return pb.PropertyType_UNKNOWN
+}
+
+// listItem is the part of a record used to order and paginate a listing.
+type listItem struct {
+	index      int
+	id         int64
+	createTime int64
+	updateTime int64
+}
+
+// paginate orders items following options and returns the indexes of the
+// requested page along with the token of the next one. Without options every
+// item is returned ordered by ID.
+func paginate(options *pb.ListOperationOptions, items []listItem) ([]int, string, error) {
+	field := pb.ListOperationOptions_OrderByField_ID
+	isAsc := true
+	if options != nil && options.OrderByField != nil {
+		field = options.GetOrderByField().GetField()
+		isAsc = options.GetOrderByField().GetIsAsc()
+	}
+	if field == pb.ListOperationOptions_OrderByField_FIELD_UNSPECIFIED {
+		field = pb.ListOperationOptions_OrderByField_ID
+	}
+
+	key := func(item listItem) int64 {
+		switch field {
+		case pb.ListOperationOptions_OrderByField_CREATE_TIME:
+			return item.createTime
+		case pb.ListOperationOptions_OrderByField_LAST_UPDATE_TIME:
+			return item.updateTime
+		}
+		return item.id
+	}
+	less := func(a, b listItem) bool {
+		if key(a) != key(b) {
+			return (key(a) < key(b)) == isAsc
+		}
+		if a.id != b.id {
+			return (a.id < b.id) == isAsc
+		}
+		return false
+	}
+	sort.Slice(items, func(i, j int) bool { return less(items[i], items[j]) })
+
+	if options == nil {
+		indexes := make([]int, len(items))
+		for i, item := range items {
+			indexes[i] = item.index
+		}
+		return indexes, "", nil
+	}
+
+	pageSize := int(options.GetMaxResultSize())
+	if pageSize <= 0 {
+		return nil, "", status.Errorf(codes.InvalidArgument, "max_result_size must be positive, got %d", pageSize)
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	start := 0
+	if token := options.GetNextPageToken(); token != "" {
+		fieldOffset, idOffset, err := decodePageToken(token)
+		if err != nil {
+			return nil, "", err
+		}
+		last := listItem{id: idOffset, createTime: fieldOffset, updateTime: fieldOffset}
+		if field == pb.ListOperationOptions_OrderByField_ID {
+			last.id = fieldOffset
+		}
+		start = sort.Search(len(items), func(i int) bool { return less(last, items[i]) })
+	}
+
+	end := start + pageSize
+	if end > len(items) {
+		end = len(items)
+	}
+
+	var indexes []int
+	for _, item := range items[start:end] {
+		indexes = append(indexes, item.index)
+	}
+
+	nextPageToken := ""
+	if end < len(items) {
+		lastItem := items[end-1]
+		nextPageToken = encodePageToken(key(lastItem), lastItem.id)
+	}
+	return indexes, nextPageToken, nil
+}
+
+func encodePageToken(fieldOffset int64, idOffset int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", fieldOffset, idOffset)))
+}
+
+func decodePageToken(token string) (int64, int64, error) {
+	invalid := status.Errorf(codes.InvalidArgument, "invalid next_page_token %q", token)
+
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, 0, invalid
+	}
+	parts := strings.Split(string(decoded), ":")
+	if len(parts) != 2 {
+		return 0, 0, invalid
+	}
+	fieldOffset, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, invalid
+	}
+	idOffset, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, invalid
+	}
+	return fieldOffset, idOffset, nil
+}
+
+func sortedIDs(ids []int64) []int64 {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func cloneArtifact(artifact *pb.Artifact) *pb.Artifact {
+	return proto.Clone(artifact).(*pb.Artifact)
+}
+
+func cloneExecution(execution *pb.Execution) *pb.Execution {
+	return proto.Clone(execution).(*pb.Execution)
+}
+
+func cloneContext(ctx *pb.Context) *pb.Context {
+	return proto.Clone(ctx).(*pb.Context)
+}
@@ -0,0 +1,114 @@
+package mlmdtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+)
+
+func TestGetArtifactsPagination(t *testing.T) {
+	mlmd := NewStore()
+	mlmd.SeedKubeflowTypes()
+	for i := 0; i < 5; i++ {
+		mlmd.SeedArtifact(Artifact{Type: ModelType, Name: fmt.Sprintf("model-%d", i)})
+	}
+
+	for _, isAsc := range []bool{true, false} {
+		options := &pb.ListOperationOptions{
+			MaxResultSize: proto.Int32(2),
+			OrderByField: &pb.ListOperationOptions_OrderByField{
+				Field: pb.ListOperationOptions_OrderByField_CREATE_TIME.Enum(),
+				IsAsc: proto.Bool(isAsc),
+			},
+		}
+
+		var ids []int64
+		pages := 0
+		for {
+			response, err := mlmd.GetArtifacts(context.Background(), &pb.GetArtifactsRequest{Options: options})
+			if err != nil {
+				t.Fatal(err)
+			}
+			pages++
+			for _, artifact := range response.GetArtifacts() {
+				ids = append(ids, artifact.GetId())
+			}
+			if response.GetNextPageToken() == "" {
+				break
+			}
+			options.NextPageToken = proto.String(response.GetNextPageToken())
+		}
+
+		want := []int64{1, 2, 3, 4, 5}
+		if !isAsc {
+			want = []int64{5, 4, 3, 2, 1}
+		}
+		if pages != 3 || fmt.Sprint(ids) != fmt.Sprint(want) {
+			t.Errorf("isAsc=%v: got %v in %d pages, want %v in 3 pages", isAsc, ids, pages, want)
+		}
+	}
+}
+
+func TestPutExecutionRollsBack(t *testing.T) {
+	mlmd := NewStore()
+	mlmd.SeedKubeflowTypes()
+	workspaceID := mlmd.SeedWorkspace("workspace_1")
+	modelType := mlmd.findArtifactType(ModelType)
+	executionType := mlmd.findExecutionType(ExecutionType)
+
+	_, err := mlmd.PutExecution(context.Background(), &pb.PutExecutionRequest{
+		Execution: &pb.Execution{TypeId: executionType.Id},
+		ArtifactEventPairs: []*pb.PutExecutionRequest_ArtifactAndEvent{{
+			Artifact: &pb.Artifact{TypeId: modelType.Id, Uri: proto.String("gcs://my-bucket/model")},
+			Event:    &pb.Event{Type: pb.Event_OUTPUT.Enum()},
+		}},
+		Contexts: []*pb.Context{{Id: proto.Int64(workspaceID + 100)}},
+	})
+	if status.Code(err) == codes.OK {
+		t.Fatal("PutExecution with an unknown context succeeded")
+	}
+
+	artifacts, _ := mlmd.GetArtifacts(context.Background(), &pb.GetArtifactsRequest{})
+	executions, _ := mlmd.GetExecutions(context.Background(), &pb.GetExecutionsRequest{})
+	if len(artifacts.GetArtifacts()) != 0 || len(executions.GetExecutions()) != 0 {
+		t.Errorf("failed PutExecution left %d artifacts and %d executions", len(artifacts.GetArtifacts()), len(executions.GetExecutions()))
+	}
+}
+
+func TestPutArtifactsUndeclaredProperty(t *testing.T) {
+	mlmd := NewStore()
+	mlmd.SeedKubeflowTypes()
+	modelType := mlmd.findArtifactType(ModelType)
+
+	_, err := mlmd.PutArtifacts(context.Background(), &pb.PutArtifactsRequest{
+		Artifacts: []*pb.Artifact{{
+			TypeId:     modelType.Id,
+			Properties: map[string]*pb.Value{"accuracy": DoubleValue(0.9)},
+		}},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("PutArtifacts error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestSetError(t *testing.T) {
+	mlmd := NewStore()
+	mlmd.SetError("GetArtifactTypes", status.Error(codes.Unavailable, "down"))
+
+	if _, err := mlmd.GetArtifactTypes(context.Background(), &pb.GetArtifactTypesRequest{}); status.Code(err) != codes.Unavailable {
+		t.Errorf("GetArtifactTypes error = %v, want Unavailable", err)
+	}
+	mlmd.SetError("GetArtifactTypes", nil)
+	if _, err := mlmd.GetArtifactTypes(context.Background(), &pb.GetArtifactTypesRequest{}); err != nil {
+		t.Errorf("GetArtifactTypes error = %v after clearing it", err)
+	}
+	if calls := mlmd.Calls("GetArtifactTypes"); calls != 2 {
+		t.Errorf("Calls = %d, want 2", calls)
+	}
+}
@@ -0,0 +1,401 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Artifact, execution and context types
+
+package mlmdtest
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+)
+
+// mergeSchema returns the properties of a stored type updated with the
+// requested ones, following the can_add_fields and can_omit_fields flags.
+func mergeSchema(name string, stored, requested map[string]pb.PropertyType, canAddFields, canOmitFields bool) (map[string]pb.PropertyType, error) {
+	merged := make(map[string]pb.PropertyType, len(stored))
+	for property, propertyType := range stored {
+		requestedType, ok := requested[property]
+		if !ok && !canOmitFields {
+			return nil, status.Errorf(codes.AlreadyExists, "type %s has property %s which is missing from the request", name, property)
+		}
+		if ok && requestedType != propertyType {
+			return nil, status.Errorf(codes.AlreadyExists, "type %s has property %s of type %s", name, property, propertyType)
+		}
+		merged[property] = propertyType
+	}
+	for property, propertyType := range requested {
+		if _, ok := stored[property]; ok {
+			continue
+		}
+		if !canAddFields {
+			return nil, status.Errorf(codes.AlreadyExists, "type %s does not have property %s", name, property)
+		}
+		merged[property] = propertyType
+	}
+	return merged, nil
+}
+
+func (s *Store) putArtifactType(artifactType *pb.ArtifactType, canAddFields, canOmitFields bool) (int64, error) {
+	if artifactType.GetName() == "" {
+		return 0, status.Error(codes.InvalidArgument, "artifact type name is required")
+	}
+	if stored := s.findArtifactType(artifactType.GetName()); stored != nil {
+		properties, err := mergeSchema(stored.GetName(), stored.GetProperties(), artifactType.GetProperties(), canAddFields, canOmitFields)
+		if err != nil {
+			return 0, err
+		}
+		updated := proto.Clone(stored).(*pb.ArtifactType)
+		updated.Properties = properties
+		s.state.artifactTypes[stored.GetId()] = updated
+		return stored.GetId(), nil
+	}
+
+	stored := proto.Clone(artifactType).(*pb.ArtifactType)
+	id := s.state.nextTypeID
+	s.state.nextTypeID++
+	stored.Id = &id
+	s.state.artifactTypes[id] = stored
+	return id, nil
+}
+
+func (s *Store) putExecutionType(executionType *pb.ExecutionType, canAddFields, canOmitFields bool) (int64, error) {
+	if executionType.GetName() == "" {
+		return 0, status.Error(codes.InvalidArgument, "execution type name is required")
+	}
+	if stored := s.findExecutionType(executionType.GetName()); stored != nil {
+		properties, err := mergeSchema(stored.GetName(), stored.GetProperties(), executionType.GetProperties(), canAddFields, canOmitFields)
+		if err != nil {
+			return 0, err
+		}
+		updated := proto.Clone(stored).(*pb.ExecutionType)
+		updated.Properties = properties
+		s.state.executionTypes[stored.GetId()] = updated
+		return stored.GetId(), nil
+	}
+
+	stored := proto.Clone(executionType).(*pb.ExecutionType)
+	id := s.state.nextTypeID
+	s.state.nextTypeID++
+	stored.Id = &id
+	s.state.executionTypes[id] = stored
+	return id, nil
+}
+
+func (s *Store) putContextType(contextType *pb.ContextType, canAddFields, canOmitFields bool) (int64, error) {
+	if contextType.GetName() == "" {
+		return 0, status.Error(codes.InvalidArgument, "context type name is required")
+	}
+	if stored := s.findContextType(contextType.GetName()); stored != nil {
+		properties, err := mergeSchema(stored.GetName(), stored.GetProperties(), contextType.GetProperties(), canAddFields, canOmitFields)
+		if err != nil {
+			return 0, err
+		}
+		updated := proto.Clone(stored).(*pb.ContextType)
+		updated.Properties = properties
+		s.state.contextTypes[stored.GetId()] = updated
+		return stored.GetId(), nil
+	}
+
+	stored := proto.Clone(contextType).(*pb.ContextType)
+	id := s.state.nextTypeID
+	s.state.nextTypeID++
+	stored.Id = &id
+	s.state.contextTypes[id] = stored
+	return id, nil
+}
+
+func (s *Store) findArtifactType(name string) *pb.ArtifactType {
+	for _, artifactType := range s.state.artifactTypes {
+		if artifactType.GetName() == name {
+			return artifactType
+		}
+	}
+	return nil
+}
+
+func (s *Store) findExecutionType(name string) *pb.ExecutionType {
+	for _, executionType := range s.state.executionTypes {
+		if executionType.GetName() == name {
+			return executionType
+		}
+	}
+	return nil
+}
+
+func (s *Store) findContextType(name string) *pb.ContextType {
+	for _, contextType := range s.state.contextTypes {
+		if contextType.GetName() == name {
+			return contextType
+		}
+	}
+	return nil
+}
+
+// PutArtifactType creates an artifact type or updates the stored one with
+// the same name.
+func (s *Store) PutArtifactType(ctx context.Context, in *pb.PutArtifactTypeRequest, opts ...grpc.CallOption) (*pb.PutArtifactTypeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "PutArtifactType"); err != nil {
+		return nil, err
+	}
+
+	id, err := s.putArtifactType(in.GetArtifactType(), in.GetCanAddFields(), in.GetCanOmitFields())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.PutArtifactTypeResponse{TypeId: &id}, nil
+}
+
+// PutExecutionType creates an execution type or updates the stored one with
+// the same name.
+func (s *Store) PutExecutionType(ctx context.Context, in *pb.PutExecutionTypeRequest, opts ...grpc.CallOption) (*pb.PutExecutionTypeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "PutExecutionType"); err != nil {
+		return nil, err
+	}
+
+	id, err := s.putExecutionType(in.GetExecutionType(), in.GetCanAddFields(), in.GetCanOmitFields())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.PutExecutionTypeResponse{TypeId: &id}, nil
+}
+
+// PutContextType creates a context type or updates the stored one with the
+// same name.
+func (s *Store) PutContextType(ctx context.Context, in *pb.PutContextTypeRequest, opts ...grpc.CallOption) (*pb.PutContextTypeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "PutContextType"); err != nil {
+		return nil, err
+	}
+
+	id, err := s.putContextType(in.GetContextType(), in.GetCanAddFields(), in.GetCanOmitFields())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.PutContextTypeResponse{TypeId: &id}, nil
+}
+
+// PutTypes creates or updates artifact, execution and context types at once.
+func (s *Store) PutTypes(ctx context.Context, in *pb.PutTypesRequest, opts ...grpc.CallOption) (*pb.PutTypesResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "PutTypes"); err != nil {
+		return nil, err
+	}
+
+	response := &pb.PutTypesResponse{}
+	err := s.transaction(func() error {
+		for _, artifactType := range in.GetArtifactTypes() {
+			id, err := s.putArtifactType(artifactType, in.GetCanAddFields(), in.GetCanOmitFields())
+			if err != nil {
+				return err
+			}
+			response.ArtifactTypeIds = append(response.ArtifactTypeIds, id)
+		}
+		for _, executionType := range in.GetExecutionTypes() {
+			id, err := s.putExecutionType(executionType, in.GetCanAddFields(), in.GetCanOmitFields())
+			if err != nil {
+				return err
+			}
+			response.ExecutionTypeIds = append(response.ExecutionTypeIds, id)
+		}
+		for _, contextType := range in.GetContextTypes() {
+			id, err := s.putContextType(contextType, in.GetCanAddFields(), in.GetCanOmitFields())
+			if err != nil {
+				return err
+			}
+			response.ContextTypeIds = append(response.ContextTypeIds, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetArtifactType returns the artifact type with the requested name.
+func (s *Store) GetArtifactType(ctx context.Context, in *pb.GetArtifactTypeRequest, opts ...grpc.CallOption) (*pb.GetArtifactTypeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetArtifactType"); err != nil {
+		return nil, err
+	}
+
+	artifactType := s.findArtifactType(in.GetTypeName())
+	if artifactType == nil {
+		return nil, status.Errorf(codes.NotFound, "no artifact type named %s", in.GetTypeName())
+	}
+	return &pb.GetArtifactTypeResponse{ArtifactType: proto.Clone(artifactType).(*pb.ArtifactType)}, nil
+}
+
+// GetArtifactTypesByID returns the artifact types with the requested IDs.
+func (s *Store) GetArtifactTypesByID(ctx context.Context, in *pb.GetArtifactTypesByIDRequest, opts ...grpc.CallOption) (*pb.GetArtifactTypesByIDResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetArtifactTypesByID"); err != nil {
+		return nil, err
+	}
+
+	response := &pb.GetArtifactTypesByIDResponse{}
+	for _, id := range in.GetTypeIds() {
+		if artifactType, ok := s.state.artifactTypes[id]; ok {
+			response.ArtifactTypes = append(response.ArtifactTypes, proto.Clone(artifactType).(*pb.ArtifactType))
+		}
+	}
+	return response, nil
+}
+
+// GetArtifactTypes returns every artifact type.
+func (s *Store) GetArtifactTypes(ctx context.Context, in *pb.GetArtifactTypesRequest, opts ...grpc.CallOption) (*pb.GetArtifactTypesResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetArtifactTypes"); err != nil {
+		return nil, err
+	}
+
+	response := &pb.GetArtifactTypesResponse{}
+	for _, id := range sortedIDs(artifactTypeIDs(s.state.artifactTypes)) {
+		response.ArtifactTypes = append(response.ArtifactTypes, proto.Clone(s.state.artifactTypes[id]).(*pb.ArtifactType))
+	}
+	return response, nil
+}
+
+// GetExecutionType returns the execution type with the requested name.
+func (s *Store) GetExecutionType(ctx context.Context, in *pb.GetExecutionTypeRequest, opts ...grpc.CallOption) (*pb.GetExecutionTypeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetExecutionType"); err != nil {
+		return nil, err
+	}
+
+	executionType := s.findExecutionType(in.GetTypeName())
+	if executionType == nil {
+		return nil, status.Errorf(codes.NotFound, "no execution type named %s", in.GetTypeName())
+	}
+	return &pb.GetExecutionTypeResponse{ExecutionType: proto.Clone(executionType).(*pb.ExecutionType)}, nil
+}
+
+// GetExecutionTypesByID returns the execution types with the requested IDs.
+func (s *Store) GetExecutionTypesByID(ctx context.Context, in *pb.GetExecutionTypesByIDRequest, opts ...grpc.CallOption) (*pb.GetExecutionTypesByIDResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetExecutionTypesByID"); err != nil {
+		return nil, err
+	}
+
+	response := &pb.GetExecutionTypesByIDResponse{}
+	for _, id := range in.GetTypeIds() {
+		if executionType, ok := s.state.executionTypes[id]; ok {
+			response.ExecutionTypes = append(response.ExecutionTypes, proto.Clone(executionType).(*pb.ExecutionType))
+		}
+	}
+	return response, nil
+}
+
+// GetExecutionTypes returns every execution type.
+func (s *Store) GetExecutionTypes(ctx context.Context, in *pb.GetExecutionTypesRequest, opts ...grpc.CallOption) (*pb.GetExecutionTypesResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetExecutionTypes"); err != nil {
+		return nil, err
+	}
+
+	response := &pb.GetExecutionTypesResponse{}
+	for _, id := range sortedIDs(executionTypeIDs(s.state.executionTypes)) {
+		response.ExecutionTypes = append(response.ExecutionTypes, proto.Clone(s.state.executionTypes[id]).(*pb.ExecutionType))
+	}
+	return response, nil
+}
+
+// GetContextType returns the context type with the requested name.
+func (s *Store) GetContextType(ctx context.Context, in *pb.GetContextTypeRequest, opts ...grpc.CallOption) (*pb.GetContextTypeResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetContextType"); err != nil {
+		return nil, err
+	}
+
+	contextType := s.findContextType(in.GetTypeName())
+	if contextType == nil {
+		return nil, status.Errorf(codes.NotFound, "no context type named %s", in.GetTypeName())
+	}
+	return &pb.GetContextTypeResponse{ContextType: proto.Clone(contextType).(*pb.ContextType)}, nil
+}
+
+// GetContextTypesByID returns the context types with the requested IDs.
+func (s *Store) GetContextTypesByID(ctx context.Context, in *pb.GetContextTypesByIDRequest, opts ...grpc.CallOption) (*pb.GetContextTypesByIDResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetContextTypesByID"); err != nil {
+		return nil, err
+	}
+
+	response := &pb.GetContextTypesByIDResponse{}
+	for _, id := range in.GetTypeIds() {
+		if contextType, ok := s.state.contextTypes[id]; ok {
+			response.ContextTypes = append(response.ContextTypes, proto.Clone(contextType).(*pb.ContextType))
+		}
+	}
+	return response, nil
+}
+
+// GetContextTypes returns every context type.
+func (s *Store) GetContextTypes(ctx context.Context, in *pb.GetContextTypesRequest, opts ...grpc.CallOption) (*pb.GetContextTypesResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.call(ctx, "GetContextTypes"); err != nil {
+		return nil, err
+	}
+
+	response := &pb.GetContextTypesResponse{}
+	for _, id := range sortedIDs(contextTypeIDs(s.state.contextTypes)) {
+		response.ContextTypes = append(response.ContextTypes, proto.Clone(s.state.contextTypes[id]).(*pb.ContextType))
+	}
+	return response, nil
+}
+
+func artifactTypeIDs(types map[int64]*pb.ArtifactType) []int64 {
+	var ids []int64
+	for id := range types {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func executionTypeIDs(types map[int64]*pb.ExecutionType) []int64 {
+	var ids []int64
+	for id := range types {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func contextTypeIDs(types map[int64]*pb.ContextType) []int64 {
+	var ids []int64
+	for id := range types {
+		ids = append(ids, id)
+	}
+	return ids
+}
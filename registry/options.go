@@ -0,0 +1,284 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Options to configure the connection to MLMD
+
+package artifact_registry
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+)
+
+// Option configures an MLArtifactStore created by NewArtifactStore.
+type Option func(*storeOptions)
+
+type storeOptions struct {
+	host string
+	port string
+
+	tls        bool
+	tlsConfig  *tls.Config
+	customCA   []byte
+	clientCert []byte
+	clientKey  []byte
+
+	keepalive      *keepalive.ClientParameters
+	maxRecvMsgSize int
+	timeout        time.Duration
+	blockTimeout   time.Duration
+	dialOptions    []grpc.DialOption
+
+	client pb.MetadataStoreServiceClient
+
+	typeMapping  map[string]pb.ArtifactData_ArtifactType
+	typeCacheTTL time.Duration
+
+	blobStores BlobStores
+}
+
+// WithAddress sets the host and port of the MLMD gRPC server.
+func WithAddress(host string, port string) Option {
+	return func(options *storeOptions) {
+		options.host = host
+		options.port = port
+	}
+}
+
+// WithTLS connects to MLMD over TLS using the given configuration. A nil
+// configuration uses the system root CAs.
+func WithTLS(config *tls.Config) Option {
+	return func(options *storeOptions) {
+		options.tls = true
+		options.tlsConfig = config
+	}
+}
+
+// WithCustomCA connects to MLMD over TLS and verifies the server against the
+// PEM encoded root certificates instead of the system ones.
+func WithCustomCA(caPEM []byte) Option {
+	return func(options *storeOptions) {
+		options.tls = true
+		options.customCA = caPEM
+	}
+}
+
+// WithClientCertificate connects to MLMD over mutual TLS presenting the PEM
+// encoded certificate chain and private key.
+func WithClientCertificate(certPEM []byte, keyPEM []byte) Option {
+	return func(options *storeOptions) {
+		options.tls = true
+		options.clientCert = certPEM
+		options.clientKey = keyPEM
+	}
+}
+
+// WithKeepalive sets the keepalive parameters of the gRPC channel.
+func WithKeepalive(params keepalive.ClientParameters) Option {
+	return func(options *storeOptions) {
+		options.keepalive = &params
+	}
+}
+
+// WithMaxReceiveMessageSize sets the maximum size in bytes of a response the
+// client accepts from MLMD.
+func WithMaxReceiveMessageSize(size int) Option {
+	return func(options *storeOptions) {
+		options.maxRecvMsgSize = size
+	}
+}
+
+// WithTimeout sets the default timeout of every call made to MLMD over the
+// connection dialed by NewArtifactStore. It only applies to calls whose
+// context has no deadline. Zero disables the timeout. It is ignored with
+// WithClient, except to bound the cleanups following a failure.
+func WithTimeout(timeout time.Duration) Option {
+	return func(options *storeOptions) {
+		options.timeout = timeout
+	}
+}
+
+// WithBlock makes NewArtifactStore wait up to timeout for the connection to
+// MLMD to be ready, and return an error if it is not.
+func WithBlock(timeout time.Duration) Option {
+	return func(options *storeOptions) {
+		options.blockTimeout = timeout
+	}
+}
+
+// WithDialOptions appends arbitrary options used when dialing MLMD.
+func WithDialOptions(dialOptions ...grpc.DialOption) Option {
+	return func(options *storeOptions) {
+		options.dialOptions = append(options.dialOptions, dialOptions...)
+	}
+}
+
+// WithClient makes the store use an existing MLMD client, such as the
+// in-memory one of package mlmdtest, instead of dialing MLMD. The connection
+// options are ignored, including WithTimeout: the client or the contexts
+// passed to the WithContext methods must bound the calls.
+func WithClient(client pb.MetadataStoreServiceClient) Option {
+	return func(options *storeOptions) {
+		options.client = client
+	}
+}
+
+// WithArtifactTypeMapping maps MLMD artifact type names to ArtifactData
+// types on top of DefaultArtifactTypeMapping. Map a type to OTHER to remove it
+// from the defaults.
+func WithArtifactTypeMapping(typeMapping map[string]pb.ArtifactData_ArtifactType) Option {
+	return func(options *storeOptions) {
+		for typeName, artifactType := range typeMapping {
+			options.typeMapping[typeName] = artifactType
+		}
+	}
+}
+
+// WithTypeCacheTTL sets how long the types of MLMD are cached for, 10 minutes
+// by default. A zero TTL fetches the types on every call.
+func WithTypeCacheTTL(ttl time.Duration) Option {
+	return func(options *storeOptions) {
+		options.typeCacheTTL = ttl
+	}
+}
+
+// WithBlobStore reads the payload of artifacts whose URI has one of the
+// schemes from store, e.g.
+//
+//	WithBlobStore(gcsStore, "gcs", "gs")
+//
+// Local paths and file:// URIs are read from the local filesystem unless
+// overridden for the "file" scheme.
+func WithBlobStore(store BlobStore, schemes ...string) Option {
+	return func(options *storeOptions) {
+		for _, scheme := range schemes {
+			options.blobStores[strings.ToLower(scheme)] = store
+		}
+	}
+}
+
+// WithClientConfig applies an MLMD client configuration: address, SSL
+// configuration, channel arguments and client timeout. Unset fields leave the
+// corresponding options untouched, except client_timeout_sec which disables
+// the timeout when unset as documented by MLMD. Pass WithTimeout after this
+// option to keep one.
+func WithClientConfig(config *pb.MetadataStoreClientConfig) Option {
+	return func(options *storeOptions) {
+		if config.Host != nil {
+			options.host = config.GetHost()
+		}
+		if config.Port != nil {
+			options.port = strconv.FormatUint(uint64(config.GetPort()), 10)
+		}
+		if sslConfig := config.GetSslConfig(); sslConfig != nil {
+			options.tls = true
+			if sslConfig.GetCustomCa() != "" {
+				options.customCA = []byte(sslConfig.GetCustomCa())
+			}
+			if sslConfig.GetServerCert() != "" || sslConfig.GetClientKey() != "" {
+				options.clientCert = []byte(sslConfig.GetServerCert())
+				options.clientKey = []byte(sslConfig.GetClientKey())
+			}
+		}
+		if size := config.GetChannelArguments().GetMaxReceiveMessageLength(); size > 0 {
+			options.maxRecvMsgSize = int(size)
+		}
+		options.timeout = time.Duration(config.GetClientTimeoutSec() * float64(time.Second))
+	}
+}
+
+func defaultOptions() *storeOptions {
+	return &storeOptions{
+		host:         "localhost",
+		port:         "8080",
+		timeout:      defaultTimeout,
+		typeMapping:  DefaultArtifactTypeMapping(),
+		typeCacheTTL: defaultTypeCacheTTL,
+		blobStores:   defaultBlobStores(),
+	}
+}
+
+// grpcDialOptions translates the options to the options passed to grpc.Dial.
+func (options *storeOptions) grpcDialOptions() ([]grpc.DialOption, error) {
+	var dialOptions []grpc.DialOption
+
+	if options.tls {
+		tlsConfig, err := options.buildTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		dialOptions = append(dialOptions, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		dialOptions = append(dialOptions, grpc.WithInsecure())
+	}
+
+	if options.keepalive != nil {
+		dialOptions = append(dialOptions, grpc.WithKeepaliveParams(*options.keepalive))
+	}
+	if options.maxRecvMsgSize > 0 {
+		dialOptions = append(dialOptions, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(options.maxRecvMsgSize)))
+	}
+	if options.timeout > 0 {
+		dialOptions = append(dialOptions, grpc.WithChainUnaryInterceptor(timeoutInterceptor(options.timeout)))
+	}
+
+	return append(dialOptions, options.dialOptions...), nil
+}
+
+func (options *storeOptions) buildTLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+	if options.tlsConfig != nil {
+		tlsConfig = options.tlsConfig.Clone()
+	}
+
+	if len(options.customCA) > 0 {
+		certPool := x509.NewCertPool()
+		if !certPool.AppendCertsFromPEM(options.customCA) {
+			return nil, errors.New("failed to parse custom CA certificates")
+		}
+		tlsConfig.RootCAs = certPool
+	}
+
+	if len(options.clientCert) > 0 || len(options.clientKey) > 0 {
+		certificate, err := tls.X509KeyPair(options.clientCert, options.clientKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = append(tlsConfig.Certificates, certificate)
+	}
+
+	return tlsConfig, nil
+}
+
+// timeoutInterceptor bounds every call without a deadline to timeout.
+func timeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
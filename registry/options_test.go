@@ -0,0 +1,67 @@
+package artifact_registry
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+)
+
+func TestWithClientConfig(t *testing.T) {
+	host := "mlmd.kubeflow"
+	port := uint32(8443)
+	timeout := 2.5
+	maxLength := int64(16 << 20)
+	customCA := "not a certificate"
+
+	options := defaultOptions()
+	WithClientConfig(&pb.MetadataStoreClientConfig{
+		Host:             &host,
+		Port:             &port,
+		ClientTimeoutSec: &timeout,
+		ChannelArguments: &pb.GrpcChannelArguments{MaxReceiveMessageLength: &maxLength},
+		SslConfig:        &pb.MetadataStoreClientConfig_SSLConfig{CustomCa: &customCA},
+	})(options)
+
+	if options.host != host || options.port != "8443" {
+		t.Errorf("address = %s:%s, want %s:%d", options.host, options.port, host, port)
+	}
+	if options.timeout != 2500*time.Millisecond {
+		t.Errorf("timeout = %v, want 2.5s", options.timeout)
+	}
+	if options.maxRecvMsgSize != int(maxLength) {
+		t.Errorf("maxRecvMsgSize = %d, want %d", options.maxRecvMsgSize, maxLength)
+	}
+	if !options.tls {
+		t.Error("tls is not enabled by ssl_config")
+	}
+
+	if _, err := options.grpcDialOptions(); err == nil {
+		t.Error("expected an error for an invalid custom CA")
+	}
+
+	// An unset client timeout is infinite
+	options = defaultOptions()
+	WithClientConfig(&pb.MetadataStoreClientConfig{Host: &host})(options)
+	if options.timeout != 0 {
+		t.Errorf("timeout = %v without client_timeout_sec, want none", options.timeout)
+	}
+	WithTimeout(time.Second)(options)
+	if options.timeout != time.Second {
+		t.Errorf("timeout = %v after WithTimeout, want 1s", options.timeout)
+	}
+}
+
+func TestNewArtifactStoreInvalidCertificate(t *testing.T) {
+	_, err := NewArtifactStore(WithClientCertificate([]byte("cert"), []byte("key")))
+	if err == nil {
+		t.Error("expected an error for an invalid client certificate")
+	}
+}
+
+func TestNewArtifactStoreWithBlockUnreachable(t *testing.T) {
+	_, err := NewArtifactStore(WithAddress("127.0.0.1", "1"), WithBlock(200*time.Millisecond))
+	if err == nil {
+		t.Error("expected an error when MLMD is unreachable")
+	}
+}
@@ -0,0 +1,297 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Methods to register artifacts
+
+package artifact_registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Vernacular-ai/vcore/log"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+)
+
+// ArtifactSpec describes an artifact to register in a workspace
+type ArtifactSpec struct {
+	Name    string
+	Uri     string
+	Version string
+	RunId   string
+
+	// SHA-256 digest of the payload at Uri, e.g. sha256:9f86d08..., and its
+	// size in bytes, see BlobStores.ComputeDigest. The size is optional, it
+	// is only recorded along with a digest when positive.
+	Digest string
+	Size   int64
+
+	// Additional properties, those defined by the artifact type are stored as
+	// properties and the others as custom properties
+	Properties map[string]*pb.Value
+}
+
+// RegisterModel creates a model artifact in this workspace.
+//
+// Registration is not atomic: MLMD creates the artifact, then attributes it
+// to the workspace in a second call. If the attribution fails, the artifact
+// is marked DELETED and loses its workspace property. If that cleanup fails
+// too, which is only logged, an artifact attributed to no workspace remains
+// in MLMD. Workspace listings do not return it.
+func (workspace Workspace) RegisterModel(spec ArtifactSpec) (*pb.ArtifactData, error) {
+	return workspace.RegisterModelWithContext(context.Background(), spec)
+}
+
+// RegisterModelWithContext is RegisterModel using the provided context for
+// deadlines and cancellation.
+func (workspace Workspace) RegisterModelWithContext(ctx context.Context, spec ArtifactSpec) (*pb.ArtifactData, error) {
+	artifactData, err := workspace.registerArtifact(ctx, MODEL_ARTIFACT_TYPE_NAME, spec, pb.Artifact_UNKNOWN)
+	if err != nil {
+		return artifactData, wrapError("RegisterModel", err)
+	}
+	return artifactData, nil
+}
+
+// RegisterDataset creates a dataset artifact in this workspace, see
+// RegisterModel for the failure modes.
+func (workspace Workspace) RegisterDataset(spec ArtifactSpec) (*pb.ArtifactData, error) {
+	return workspace.RegisterDatasetWithContext(context.Background(), spec)
+}
+
+// RegisterDatasetWithContext is RegisterDataset using the provided context for
+// deadlines and cancellation.
+func (workspace Workspace) RegisterDatasetWithContext(ctx context.Context, spec ArtifactSpec) (*pb.ArtifactData, error) {
+	artifactData, err := workspace.registerArtifact(ctx, DATASET_ARTIFACT_TYPE_NAME, spec, pb.Artifact_UNKNOWN)
+	if err != nil {
+		return artifactData, wrapError("RegisterDataset", err)
+	}
+	return artifactData, nil
+}
+
+// RegisterMetrics creates a metrics artifact in this workspace, see
+// RegisterModel for the failure modes.
+func (workspace Workspace) RegisterMetrics(spec ArtifactSpec) (*pb.ArtifactData, error) {
+	return workspace.RegisterMetricsWithContext(context.Background(), spec)
+}
+
+// RegisterMetricsWithContext is RegisterMetrics using the provided context for
+// deadlines and cancellation.
+func (workspace Workspace) RegisterMetricsWithContext(ctx context.Context, spec ArtifactSpec) (*pb.ArtifactData, error) {
+	artifactData, err := workspace.registerArtifact(ctx, METRICS_ARTIFACT_TYPE_NAME, spec, pb.Artifact_UNKNOWN)
+	if err != nil {
+		return artifactData, wrapError("RegisterMetrics", err)
+	}
+	return artifactData, nil
+}
+
+// registerArtifact creates an artifact of the named type following the
+// Kubeflow conventions read by prepareArtifactsList and attributes it to the
+// workspace. The artifact type is created if MLMD does not know it yet. The
+// state of the artifact is left unset if it is UNKNOWN.
+//
+// PutExecution is the only MLMD call writing an artifact and its attribution
+// at once, and it requires an execution. Registration takes two calls
+// instead, see detachArtifact for the cleanup of a failed attribution.
+func (workspace Workspace) registerArtifact(ctx context.Context, typeName string, spec ArtifactSpec, state pb.Artifact_State) (*pb.ArtifactData, error) {
+	var artifactData *pb.ArtifactData
+
+	if spec.Name == "" {
+		return artifactData, fmt.Errorf("artifact name is required: %w", ErrInvalidArgument)
+	}
+	if spec.Digest != "" {
+		digest, err := normalizeDigest(spec.Digest)
+		if err != nil {
+			return artifactData, err
+		}
+		spec.Digest = digest
+	}
+
+	client, err := workspace.artifactStore.metadataClient()
+	if err != nil {
+		return artifactData, err
+	}
+
+	artifactType, err := workspace.artifactStore.getOrCreateArtifactType(ctx, client, typeName)
+	if err != nil {
+		return artifactData, err
+	}
+
+	artifact := newArtifact(artifactType, workspace.Name, spec)
+	if state != pb.Artifact_UNKNOWN {
+		artifact.State = state.Enum()
+	}
+
+	response, err := client.PutArtifacts(ctx, &pb.PutArtifactsRequest{Artifacts: []*pb.Artifact{artifact}})
+	if err != nil {
+		log.Debugf("Failed to create artifact %s: %v", spec.Name, err)
+		return artifactData, err
+	}
+	if len(response.GetArtifactIds()) != 1 {
+		return artifactData, fmt.Errorf("MLMD returned %d artifact IDs for 1 artifact: %w", len(response.GetArtifactIds()), ErrUnavailable)
+	}
+	artifactId := response.GetArtifactIds()[0]
+
+	attributionRequest := &pb.PutAttributionsAndAssociationsRequest{
+		Attributions: []*pb.Attribution{{ContextId: &workspace.Id, ArtifactId: &artifactId}},
+	}
+	if _, err := client.PutAttributionsAndAssociations(ctx, attributionRequest); err != nil {
+		log.Debugf("Failed to attribute artifact %d to workspace %s: %v", artifactId, workspace.Name, err)
+		workspace.detachArtifact(client, artifactId, artifact)
+		return artifactData, err
+	}
+	log.Debugf("Registered artifact %s with ID %d", spec.Name, artifactId)
+
+	artifactList, err := workspace.artifactStore.fetchArtifacts(ctx, client, []int64{artifactId})
+	if err != nil {
+		return artifactData, err
+	}
+
+	return artifactList[0], nil
+}
+
+// detachArtifact marks an artifact which could not be attributed to the
+// workspace DELETED and removes its workspace property, so that it is not
+// found by property either, as MLMD can not delete artifacts. Failures are
+// only logged as the registration already failed.
+func (workspace Workspace) detachArtifact(client pb.MetadataStoreServiceClient, artifactId int64, artifact *pb.Artifact) {
+	ctx, cancel := workspace.artifactStore.cleanupContext()
+	defer cancel()
+
+	artifact.Id = &artifactId
+	artifact.State = pb.Artifact_DELETED.Enum()
+	delete(artifact.CustomProperties, "__kf_workspace__")
+	if _, err := client.PutArtifacts(ctx, &pb.PutArtifactsRequest{Artifacts: []*pb.Artifact{artifact}}); err != nil {
+		log.Errorf(err, "Failed to mark unattributed artifact %d as deleted", artifactId)
+		return
+	}
+	log.Debugf("Marked unattributed artifact %d as deleted", artifactId)
+}
+
+// cleanupContext returns the context of the cleanups following a failure,
+// which must not be canceled along with the context of the failed call but
+// must not block forever either. It is bounded by the timeout of the store.
+func (artifactStore MLArtifactStore) cleanupContext() (context.Context, context.CancelFunc) {
+	timeout := artifactStore.timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// fetchArtifacts fetches artifacts which were just written, along with the
+// fields set by MLMD.
+func (artifactStore MLArtifactStore) fetchArtifacts(ctx context.Context, client pb.MetadataStoreServiceClient, artifactIds []int64) ([]*pb.ArtifactData, error) {
+	if len(artifactIds) == 0 {
+		return nil, nil
+	}
+
+	response, err := client.GetArtifactsByID(ctx, &pb.GetArtifactsByIDRequest{ArtifactIds: artifactIds})
+	if err != nil {
+		log.Debugf("Failed to fetch artifacts %v: %v", artifactIds, err)
+		return nil, err
+	}
+	// MLMD does not keep the order of the requested IDs
+	artifactsById := make(map[int64]*pb.Artifact)
+	for _, artifact := range response.GetArtifacts() {
+		artifactsById[artifact.GetId()] = artifact
+	}
+	artifacts := make([]*pb.Artifact, len(artifactIds))
+	for i, artifactId := range artifactIds {
+		if artifacts[i] = artifactsById[artifactId]; artifacts[i] == nil {
+			return nil, fmt.Errorf("artifact %d was not found after being written: %w", artifactId, ErrNotFound)
+		}
+	}
+
+	return artifactStore.prepareArtifactsList(ctx, client, artifacts)
+}
+
+// newArtifact builds an artifact of the given type following the Kubeflow
+// conventions read by prepareArtifactsList.
+func newArtifact(artifactType *pb.ArtifactType, workspaceName string, spec ArtifactSpec) *pb.Artifact {
+	artifact := &pb.Artifact{
+		TypeId:           artifactType.Id,
+		Uri:              &spec.Uri,
+		Properties:       map[string]*pb.Value{},
+		CustomProperties: map[string]*pb.Value{},
+	}
+	schema := artifactType.GetProperties()
+	for key, value := range spec.Properties {
+		setProperty(artifact.Properties, artifact.CustomProperties, schema, key, value)
+	}
+	setProperty(artifact.Properties, artifact.CustomProperties, schema, "name", stringValue(spec.Name))
+	if spec.Version != "" {
+		setProperty(artifact.Properties, artifact.CustomProperties, schema, "version", stringValue(spec.Version))
+	}
+	if spec.Digest != "" {
+		setProperty(artifact.Properties, artifact.CustomProperties, schema, DIGEST_PROPERTY, stringValue(spec.Digest))
+		if spec.Size > 0 {
+			setProperty(artifact.Properties, artifact.CustomProperties, schema, SIZE_PROPERTY, &pb.Value{Value: &pb.Value_IntValue{IntValue: spec.Size}})
+		}
+	}
+	artifact.CustomProperties["__kf_workspace__"] = stringValue(workspaceName)
+	if spec.RunId != "" {
+		artifact.CustomProperties["__kf_run__"] = stringValue(spec.RunId)
+	}
+	return artifact
+}
+
+// getOrCreateArtifactType fetches the named artifact type, registering it with
+// the name and version properties if it does not exist.
+func (artifactStore MLArtifactStore) getOrCreateArtifactType(ctx context.Context, client pb.MetadataStoreServiceClient, typeName string) (*pb.ArtifactType, error) {
+	types := artifactStore.typeRegistry(client)
+	artifactType, err := types.ArtifactTypeByName(ctx, typeName)
+	if err == nil {
+		return artifactType, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		log.Debugf("Failed to fetch artifact type %s: %v", typeName, err)
+		return nil, err
+	}
+
+	canAddFields := true
+	artifactType = &pb.ArtifactType{
+		Name: &typeName,
+		Properties: map[string]pb.PropertyType{
+			"name":    pb.PropertyType_STRING,
+			"version": pb.PropertyType_STRING,
+		},
+	}
+	typeResponse, err := client.PutArtifactType(ctx, &pb.PutArtifactTypeRequest{
+		ArtifactType: artifactType,
+		CanAddFields: &canAddFields,
+	})
+	if err != nil {
+		log.Debugf("Failed to create artifact type %s: %v", typeName, err)
+		return nil, err
+	}
+	artifactType.Id = typeResponse.TypeId
+	types.Invalidate()
+
+	return artifactType, nil
+}
+
+// setProperty stores a value in properties if the type schema defines it,
+// MLMD rejects undefined properties, otherwise in custom properties.
+func setProperty(properties, customProperties map[string]*pb.Value, schema map[string]pb.PropertyType, key string, value *pb.Value) {
+	if _, ok := schema[key]; ok {
+		properties[key] = value
+		return
+	}
+	customProperties[key] = value
+}
+
+func stringValue(value string) *pb.Value {
+	return &pb.Value{Value: &pb.Value_StringValue{StringValue: value}}
+}
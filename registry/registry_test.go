@@ -2,18 +2,93 @@
 package artifact_registry_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
 	pb "github.com/Vernacular-ai/artifact-registry/protos"
 	registry "github.com/Vernacular-ai/artifact-registry/registry"
+	"github.com/Vernacular-ai/artifact-registry/registry/mlmdtest"
 )
 
+// exampleMLMD returns an in-memory MLMD holding a small Kubeflow pipeline: a
+// dataset trains the MNIST model (ID 2) which is then evaluated.
+func exampleMLMD() *mlmdtest.Store {
+	mlmd := mlmdtest.NewStore()
+	mlmd.SeedKubeflowTypes()
+
+	run := "run-2021-03-30T16:50:45.608098"
+	dataset := mlmd.SeedArtifact(mlmdtest.Artifact{
+		Type:      mlmdtest.DatasetType,
+		Name:      "mnist",
+		Version:   "dataset_version_1",
+		URI:       "gcs://my-bucket/mnist-data",
+		Workspace: "workspace_1",
+		RunID:     run,
+	})
+	model := mlmd.SeedArtifact(mlmdtest.Artifact{
+		Type:      mlmdtest.ModelType,
+		Name:      "MNIST",
+		Version:   "model_version_69389a49-b841-41a3-b1b2-15b3cb8c629e",
+		URI:       "gcs://my-bucket/mnist",
+		Workspace: "workspace_1",
+		RunID:     run,
+	})
+	metrics := mlmd.SeedArtifact(mlmdtest.Artifact{
+		Type:      mlmdtest.MetricsType,
+		Name:      "MNIST-evaluation",
+		URI:       "gcs://my-bucket/mnist-eval.csv",
+		Workspace: "workspace_1",
+		RunID:     run,
+	})
+	mlmd.SeedExecution(mlmdtest.Execution{
+		Name:      "training",
+		Workspace: "workspace_1",
+		RunID:     run,
+		State:     pb.Execution_COMPLETE,
+		Inputs:    []int64{dataset},
+		Outputs:   []int64{model},
+	})
+	mlmd.SeedExecution(mlmdtest.Execution{
+		Name:      "evaluation",
+		Workspace: "workspace_1",
+		RunID:     run,
+		State:     pb.Execution_COMPLETE,
+		Inputs:    []int64{model},
+		Outputs:   []int64{metrics},
+	})
+
+	mlmd.SeedArtifact(mlmdtest.Artifact{
+		Type:      mlmdtest.ModelType,
+		Name:      "FunctionComponent",
+		URI:       "gcs://my-bucket/component",
+		Workspace: "workspace_1",
+		RunID:     "1c3ef58a-0b72-4fe8-8a92-9bf1e77ef7c3",
+	})
+	mlmd.SeedArtifact(mlmdtest.Artifact{
+		Type:      mlmdtest.ModelType,
+		Name:      "MNIST",
+		URI:       "gcs://other-bucket/mnist",
+		Workspace: "workspace_2",
+	})
+
+	return mlmd
+}
+
 // Usage guide to get artifacts by IDs
 func ExampleMLArtifactStore_GetArtifactsByID() {
-	artifactStore := registry.ArtifactStore("localhost", "8080")
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(exampleMLMD()))
 
 	artifact := &pb.MLArtifact{
-		// ArtifactType: pb.MLArtifact_MODEL,
-		Ids: []int64{5312},
+		Ids: []int64{2},
 	}
 	response, _ := artifactStore.GetArtifactsByID(artifact)
 
@@ -32,9 +107,33 @@ func ExampleMLArtifactStore_GetArtifactsByID() {
 	// MODEL
 }
 
+// Example to connect to an MLMD server fronted by TLS
+func ExampleNewArtifactStore() {
+	caPEM, err := ioutil.ReadFile("/etc/mlmd/ca.pem")
+	if err != nil {
+		return
+	}
+
+	artifactStore, err := registry.NewArtifactStore(
+		registry.WithAddress("metadata-grpc-service.kubeflow", "8080"),
+		registry.WithCustomCA(caPEM),
+		registry.WithMaxReceiveMessageSize(16<<20),
+		registry.WithTimeout(10*time.Second),
+		registry.WithBlock(30*time.Second),
+	)
+	if err != nil {
+		return
+	}
+	defer artifactStore.Close()
+
+	if err := artifactStore.Ping(context.Background()); err != nil {
+		return
+	}
+}
+
 // Example usage to find Workspace by workspace name
 func ExampleMLArtifactStore_GetWorkspace() {
-	artifactStore := registry.ArtifactStore("localhost", "8080")
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(exampleMLMD()))
 
 	workspace := &pb.Workspace{
 		Name: "workspace_1",
@@ -49,7 +148,7 @@ func ExampleMLArtifactStore_GetWorkspace() {
 
 // Example to fetch artifacts in a workspace
 func ExampleWorkspace_GetArtifactsByWorkspace() {
-	artifactStore := registry.ArtifactStore("localhost", "8080")
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(exampleMLMD()))
 
 	workspaceInfo := &pb.Workspace{
 		Name: "workspace_1",
@@ -58,16 +157,19 @@ func ExampleWorkspace_GetArtifactsByWorkspace() {
 	workspace, _ := artifactStore.GetWorkspace(workspaceInfo)
 	artifactList, _ := workspace.GetArtifactsByWorkspace()
 
-	for range artifactList.GetArtifacts() {
-		// do something
+	for _, artifactData := range artifactList.GetArtifacts() {
+		fmt.Println(artifactData.GetName(), artifactData.GetArtifactType())
 	}
 	// Output:
-	//
+	// mnist DATASET
+	// MNIST MODEL
+	// MNIST-evaluation METRICS
+	// FunctionComponent MODEL
 }
 
 // Example to fetch artifacts by type in a workspace
 func ExampleWorkspace_GetArtifactsByTypeWorkspace() {
-	artifactStore := registry.ArtifactStore("localhost", "8080")
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(exampleMLMD()))
 
 	workspaceInfo := &pb.Workspace{
 		Name: "workspace_1",
@@ -85,14 +187,12 @@ func ExampleWorkspace_GetArtifactsByTypeWorkspace() {
 	}
 	// Output:
 	// MNIST
-	// MNIST
-	// MNIST
 	// FunctionComponent
 }
 
 // Example to get lineage by run
 func ExampleWorkspace_GetLineageByRun() {
-	artifactStore := registry.ArtifactStore("localhost", "8080")
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(exampleMLMD()))
 
 	workspaceInfo := &pb.Workspace{
 		Name: "workspace_1",
@@ -115,7 +215,7 @@ func ExampleWorkspace_GetLineageByRun() {
 
 // Example to get lineage by model
 func ExampleWorkspace_GetLineageByModel() {
-	artifactStore := registry.ArtifactStore("localhost", "8080")
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(exampleMLMD()))
 
 	workspaceInfo := &pb.Workspace{
 		Name: "workspace_1",
@@ -124,7 +224,7 @@ func ExampleWorkspace_GetLineageByModel() {
 	workspace, _ := artifactStore.GetWorkspace(workspaceInfo)
 
 	artifactsByModelRequest := &pb.ArtifactsByModelRequest{
-		ModelId: 6443,
+		ModelId: 2,
 	}
 
 	artifactList, _ := workspace.GetLineageByModel(artifactsByModelRequest)
@@ -133,7 +233,1262 @@ func ExampleWorkspace_GetLineageByModel() {
 		fmt.Println(artifactData.GetId())
 	}
 	// Output:
-	// 6442
-	// 6443
-	// 6445
+	// 1
+	// 2
+	// 3
+}
+
+func TestGetWorkspaceNotFound(t *testing.T) {
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(exampleMLMD()))
+
+	_, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "missing"})
+	if !errors.Is(err, registry.ErrNotFound) {
+		t.Errorf("GetWorkspace error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetLineageByModelPropagatesErrors(t *testing.T) {
+	for _, method := range []string{"GetEventsByArtifactIDs", "GetEventsByExecutionIDs", "GetArtifactsByID", "GetArtifactTypes"} {
+		mlmd := exampleMLMD()
+		artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+		workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mlmd.SetError(method, status.Error(codes.Unavailable, "connection refused"))
+		_, err = workspace.GetLineageByModel(&pb.ArtifactsByModelRequest{ModelId: 2})
+		if !errors.Is(err, registry.ErrUnavailable) {
+			t.Errorf("%s failing: GetLineageByModel error = %v, want ErrUnavailable", method, err)
+		}
+	}
+}
+
+func TestGetLineageByRunPropagatesErrors(t *testing.T) {
+	mlmd := exampleMLMD()
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mlmd.SetError("GetArtifactsByContext", status.Error(codes.DeadlineExceeded, "too slow"))
+	_, err = workspace.GetLineageByRun(&pb.ArtifactsByRunRequest{RunId: "1c3ef58a-0b72-4fe8-8a92-9bf1e77ef7c3"})
+	if !errors.Is(err, registry.ErrTimeout) {
+		t.Errorf("GetLineageByRun error = %v, want ErrTimeout", err)
+	}
+}
+
+func TestStoresAreIndependent(t *testing.T) {
+	staging, _ := registry.NewArtifactStore(registry.WithClient(exampleMLMD()))
+	production, _ := registry.NewArtifactStore(registry.WithClient(mlmdtest.NewStore()))
+
+	if _, err := staging.GetWorkspace(&pb.Workspace{Name: "workspace_1"}); err != nil {
+		t.Errorf("staging GetWorkspace error = %v", err)
+	}
+	if _, err := production.GetWorkspace(&pb.Workspace{Name: "workspace_1"}); !errors.Is(err, registry.ErrNotFound) {
+		t.Errorf("production GetWorkspace error = %v, want ErrNotFound", err)
+	}
+}
+
+// Example to register a trained model in a workspace
+func ExampleWorkspace_RegisterModel() {
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(exampleMLMD()))
+
+	workspace, _ := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+
+	artifactData, _ := workspace.RegisterModel(registry.ArtifactSpec{
+		Name:    "MNIST",
+		Uri:     "gcs://my-bucket/mnist-v2",
+		Version: "model_version_2",
+		RunId:   "run-2021-04-02T10:12:01.000000",
+	})
+
+	fmt.Println(artifactData.GetId(), artifactData.GetName(), artifactData.GetVersion(), artifactData.GetArtifactType())
+	// Output:
+	// 6 MNIST model_version_2 MODEL
+}
+
+func TestRegisterArtifacts(t *testing.T) {
+	mlmd := mlmdtest.NewStore()
+	mlmd.SeedWorkspace("workspace_1")
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dataset, err := workspace.RegisterDataset(registry.ArtifactSpec{Name: "mnist", RunId: "run-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	metrics, err := workspace.RegisterMetrics(registry.ArtifactSpec{
+		Name:       "MNIST-evaluation",
+		Version:    "2021-04",
+		RunId:      "run-1",
+		Properties: map[string]*pb.Value{"accuracy": mlmdtest.DoubleValue(0.98)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The metrics type has no version property, it is a custom property
+	if metrics.GetVersion() != "2021-04" {
+		t.Errorf("metrics version = %q, want 2021-04", metrics.GetVersion())
+	}
+	if dataset.GetArtifactType() != pb.ArtifactData_DATASET || metrics.GetArtifactType() != pb.ArtifactData_METRICS {
+		t.Errorf("registered types = %v, %v", dataset.GetArtifactType(), metrics.GetArtifactType())
+	}
+
+	lineage, err := workspace.GetLineageByRun(&pb.ArtifactsByRunRequest{RunId: "run-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lineage.GetArtifacts()) != 2 {
+		t.Errorf("GetLineageByRun returned %d artifacts, want 2", len(lineage.GetArtifacts()))
+	}
+
+	if _, err := workspace.RegisterModel(registry.ArtifactSpec{}); !errors.Is(err, registry.ErrInvalidArgument) {
+		t.Errorf("RegisterModel without name error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func TestRegisterArtifactAttributionFailure(t *testing.T) {
+	mlmd := exampleMLMD()
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mlmd.SetError("PutAttributionsAndAssociations", status.Error(codes.Unavailable, "connection reset"))
+	if _, err := workspace.RegisterModel(registry.ArtifactSpec{Name: "orphan", Uri: "gcs://my-bucket/orphan"}); !errors.Is(err, registry.ErrUnavailable) {
+		t.Fatalf("RegisterModel error = %v, want ErrUnavailable", err)
+	}
+	mlmd.SetError("PutAttributionsAndAssociations", nil)
+
+	// The artifact written before the attribution failed is detached from
+	// the workspace
+	orphans, err := artifactStore.GetArtifactsByID(&pb.MLArtifact{Ids: []int64{6}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orphans.GetArtifacts()) != 1 || orphans.GetArtifacts()[0].GetState() != pb.ArtifactData_DELETED ||
+		orphans.GetArtifacts()[0].GetMetadata().GetFields()["__kf_workspace__"] != nil {
+		t.Errorf("unattributed artifact = %v", orphans.GetArtifacts())
+	}
+	if _, err := workspace.AddTags(6, "orphan"); !errors.Is(err, registry.ErrNotFound) {
+		t.Errorf("AddTags on the unattributed artifact error = %v, want ErrNotFound", err)
+	}
+}
+
+// Example to record a training run producing a model
+func ExampleWorkspace_RecordExecution() {
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(exampleMLMD()))
+
+	workspace, _ := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+
+	execution, _ := workspace.RecordExecution(registry.ExecutionSpec{
+		Name:     "training",
+		RunId:    "run-2021-04-02T10:12:01.000000",
+		InputIds: []int64{1},
+		NewOutputs: []registry.OutputArtifact{{
+			ArtifactType: pb.ArtifactData_MODEL,
+			ArtifactSpec: registry.ArtifactSpec{Name: "MNIST", Uri: "gcs://my-bucket/mnist-v2"},
+		}},
+	})
+	fmt.Println(execution.State, execution.Outputs[0].GetName(), execution.Outputs[0].GetRunId())
+
+	_ = workspace.UpdateExecutionState(execution.Id, pb.Execution_COMPLETE)
+	// Output:
+	// RUNNING MNIST run-2021-04-02T10:12:01.000000
+}
+
+func TestRecordExecution(t *testing.T) {
+	mlmd := exampleMLMD()
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	execution, err := workspace.RecordExecution(registry.ExecutionSpec{
+		Name:     "training",
+		RunId:    "run-2",
+		InputIds: []int64{1},
+		NewOutputs: []registry.OutputArtifact{
+			{ArtifactType: pb.ArtifactData_MODEL, ArtifactSpec: registry.ArtifactSpec{Name: "MNIST", Version: "2"}},
+			{ArtifactType: pb.ArtifactData_METRICS, ArtifactSpec: registry.ArtifactSpec{Name: "MNIST-training"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(execution.Outputs) != 2 || execution.Outputs[1].GetArtifactType() != pb.ArtifactData_METRICS {
+		t.Fatalf("RecordExecution outputs = %v", execution.Outputs)
+	}
+
+	lineage, err := workspace.GetLineageByModel(&pb.ArtifactsByModelRequest{ModelId: execution.Outputs[0].GetId()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, artifactData := range lineage.GetArtifacts() {
+		names = append(names, artifactData.GetName())
+	}
+	if fmt.Sprint(names) != "[mnist MNIST MNIST-training]" {
+		t.Errorf("lineage of recorded model = %v", names)
+	}
+
+	runArtifacts, err := workspace.GetLineageByRun(&pb.ArtifactsByRunRequest{RunId: "run-2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runArtifacts.GetArtifacts()) != 2 {
+		t.Errorf("recorded outputs in workspace = %d, want 2", len(runArtifacts.GetArtifacts()))
+	}
+
+	if err := workspace.UpdateExecutionState(execution.Id, pb.Execution_FAILED); err != nil {
+		t.Fatal(err)
+	}
+	response, _ := mlmd.GetExecutionsByID(context.Background(), &pb.GetExecutionsByIDRequest{ExecutionIds: []int64{execution.Id}})
+	if state := response.GetExecutions()[0].GetLastKnownState(); state != pb.Execution_FAILED {
+		t.Errorf("execution state = %v, want FAILED", state)
+	}
+
+	if err := workspace.UpdateExecutionState(999, pb.Execution_COMPLETE); !errors.Is(err, registry.ErrNotFound) {
+		t.Errorf("UpdateExecutionState of missing execution error = %v, want ErrNotFound", err)
+	}
+	otherWorkspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := otherWorkspace.UpdateExecutionState(execution.Id, pb.Execution_COMPLETE); !errors.Is(err, registry.ErrNotFound) {
+		t.Errorf("UpdateExecutionState from another workspace error = %v, want ErrNotFound", err)
+	}
+	response, _ = mlmd.GetExecutionsByID(context.Background(), &pb.GetExecutionsByIDRequest{ExecutionIds: []int64{execution.Id}})
+	if state := response.GetExecutions()[0].GetLastKnownState(); state != pb.Execution_FAILED {
+		t.Errorf("execution state after an update from another workspace = %v, want FAILED", state)
+	}
+}
+
+func TestRecordExecutionIsAtomic(t *testing.T) {
+	mlmd := exampleMLMD()
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, _ := workspace.GetArtifactsByWorkspace()
+
+	_, err = workspace.RecordExecution(registry.ExecutionSpec{
+		Name:     "training",
+		InputIds: []int64{999},
+		NewOutputs: []registry.OutputArtifact{
+			{ArtifactType: pb.ArtifactData_MODEL, ArtifactSpec: registry.ArtifactSpec{Name: "orphan"}},
+		},
+	})
+	if err == nil {
+		t.Fatal("RecordExecution with a missing input succeeded")
+	}
+
+	after, _ := workspace.GetArtifactsByWorkspace()
+	if len(after.GetArtifacts()) != len(before.GetArtifacts()) {
+		t.Errorf("failed RecordExecution left %d new artifacts", len(after.GetArtifacts())-len(before.GetArtifacts()))
+	}
+}
+
+// truncatingClient drops the artifact IDs returned by PutExecution.
+type truncatingClient struct {
+	pb.MetadataStoreServiceClient
+}
+
+func (client truncatingClient) PutExecution(ctx context.Context, in *pb.PutExecutionRequest, opts ...grpc.CallOption) (*pb.PutExecutionResponse, error) {
+	response, err := client.MetadataStoreServiceClient.PutExecution(ctx, in, opts...)
+	if err == nil {
+		response.ArtifactIds = nil
+	}
+	return response, err
+}
+
+// emptyPutClient drops the artifact IDs returned by PutArtifacts.
+type emptyPutClient struct {
+	pb.MetadataStoreServiceClient
+}
+
+func (client emptyPutClient) PutArtifacts(ctx context.Context, in *pb.PutArtifactsRequest, opts ...grpc.CallOption) (*pb.PutArtifactsResponse, error) {
+	response, err := client.MetadataStoreServiceClient.PutArtifacts(ctx, in, opts...)
+	if err == nil {
+		response.ArtifactIds = nil
+	}
+	return response, err
+}
+
+func TestRegisterArtifactShortResponse(t *testing.T) {
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(emptyPutClient{exampleMLMD()}))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := workspace.RegisterModel(registry.ArtifactSpec{Name: "MNIST", Version: "2"}); !errors.Is(err, registry.ErrUnavailable) {
+		t.Errorf("RegisterModel with missing artifact IDs error = %v, want ErrUnavailable", err)
+	}
+}
+
+func TestRecordExecutionShortResponse(t *testing.T) {
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(truncatingClient{exampleMLMD()}))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = workspace.RecordExecution(registry.ExecutionSpec{
+		Name: "training",
+		NewOutputs: []registry.OutputArtifact{
+			{ArtifactType: pb.ArtifactData_MODEL, ArtifactSpec: registry.ArtifactSpec{Name: "MNIST", Version: "2"}},
+		},
+	})
+	if !errors.Is(err, registry.ErrUnavailable) {
+		t.Errorf("RecordExecution with missing artifact IDs error = %v, want ErrUnavailable", err)
+	}
+}
+
+// Example to bootstrap a workspace
+func ExampleMLArtifactStore_CreateWorkspace() {
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmdtest.NewStore()))
+
+	workspace, _ := artifactStore.CreateWorkspace(registry.WorkspaceSpec{
+		Name:        "speech-recognition",
+		Description: "ASR models",
+		Owner:       "ml-platform",
+	})
+
+	fmt.Println(workspace.Name, workspace.Description, workspace.Owner)
+	// Output:
+	// speech-recognition ASR models ml-platform
+}
+
+func TestCreateWorkspaceIsIdempotent(t *testing.T) {
+	mlmd := mlmdtest.NewStore()
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+
+	created, err := artifactStore.CreateWorkspace(registry.WorkspaceSpec{Name: "workspace_1", Owner: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.CreateTime.IsZero() {
+		t.Error("created workspace has no create time")
+	}
+
+	again, err := artifactStore.CreateWorkspace(registry.WorkspaceSpec{Name: "workspace_1", Owner: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Id != created.Id || again.Owner != "alice" {
+		t.Errorf("second CreateWorkspace = %+v, want workspace %d owned by alice", again, created.Id)
+	}
+	if calls := mlmd.Calls("PutContexts"); calls != 1 {
+		t.Errorf("PutContexts called %d times, want 1", calls)
+	}
+}
+
+func TestListWorkspaces(t *testing.T) {
+	mlmd := exampleMLMD()
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	for _, name := range []string{"workspace_3", "workspace_4", "workspace_5"} {
+		if _, err := artifactStore.CreateWorkspace(registry.WorkspaceSpec{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var names []string
+	options := registry.ListOptions{PageSize: 2}
+	for {
+		workspaces, nextPageToken, err := artifactStore.ListWorkspaces(options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, workspace := range workspaces {
+			names = append(names, workspace.Name)
+		}
+		if nextPageToken == "" {
+			break
+		}
+		options.PageToken = nextPageToken
+	}
+
+	if fmt.Sprint(names) != "[workspace_1 workspace_2 workspace_3 workspace_4 workspace_5]" {
+		t.Errorf("ListWorkspaces = %v", names)
+	}
+}
+
+// Example to find the dataset a model was trained on
+func ExampleWorkspace_GetLineageGraph() {
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(exampleMLMD()))
+
+	workspace, _ := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+
+	lineageGraph, _ := workspace.GetLineageGraph(2, registry.LineageOptions{Direction: registry.Upstream})
+
+	for _, event := range lineageGraph.GetEvents() {
+		fmt.Println(event.GetArtifactId(), event.GetType(), event.GetExecutionId())
+	}
+	// Output:
+	// 1 DECLARED_INPUT 1
+	// 2 DECLARED_OUTPUT 1
+}
+
+func TestGetLineageGraph(t *testing.T) {
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(exampleMLMD()))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		artifactId int64
+		options    registry.LineageOptions
+		artifacts  string
+		executions string
+	}{
+		{3, registry.LineageOptions{Direction: registry.Upstream}, "[1 2 3]", "[1 2]"},
+		{3, registry.LineageOptions{Direction: registry.Upstream, MaxHops: 1}, "[2 3]", "[2]"},
+		{1, registry.LineageOptions{Direction: registry.Downstream}, "[1 2 3]", "[1 2]"},
+		{1, registry.LineageOptions{Direction: registry.Downstream, MaxHops: 1}, "[1 2]", "[1]"},
+		{2, registry.LineageOptions{Direction: registry.Both, MaxHops: 1}, "[1 2 3]", "[1 2]"},
+		{3, registry.LineageOptions{Direction: registry.Downstream}, "[3]", "[]"},
+	}
+	for _, test := range tests {
+		lineageGraph, err := workspace.GetLineageGraph(test.artifactId, test.options)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var artifactIds, executionIds []int64
+		for _, artifact := range lineageGraph.GetArtifacts() {
+			artifactIds = append(artifactIds, artifact.GetId())
+		}
+		for _, execution := range lineageGraph.GetExecutions() {
+			executionIds = append(executionIds, execution.GetId())
+		}
+		if fmt.Sprint(artifactIds) != test.artifacts || fmt.Sprint(executionIds) != test.executions {
+			t.Errorf("GetLineageGraph(%d, %+v) = artifacts %v executions %v, want %s %s",
+				test.artifactId, test.options, artifactIds, executionIds, test.artifacts, test.executions)
+		}
+		if len(lineageGraph.GetArtifactTypes()) == 0 {
+			t.Errorf("GetLineageGraph(%d, %+v) has no artifact types", test.artifactId, test.options)
+		}
+	}
+
+	if _, err := workspace.GetLineageGraph(999, registry.LineageOptions{}); !errors.Is(err, registry.ErrNotFound) {
+		t.Errorf("GetLineageGraph of missing artifact error = %v, want ErrNotFound", err)
+	}
+}
+
+// Example to page through the artifacts of a workspace
+func ExampleWorkspace_IterateArtifacts() {
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(exampleMLMD()))
+
+	workspace, _ := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+
+	iterator := workspace.IterateArtifacts(context.Background(), registry.ListOptions{
+		PageSize:   2,
+		OrderBy:    registry.OrderByCreateTime,
+		Descending: true,
+	})
+	for iterator.Next() {
+		fmt.Println(iterator.Artifact().GetName())
+	}
+	if err := iterator.Err(); err != nil {
+		fmt.Println(err)
+	}
+	// Output:
+	// FunctionComponent
+	// MNIST-evaluation
+	// MNIST
+	// mnist
+}
+
+func TestListArtifactsByType(t *testing.T) {
+	mlmd := exampleMLMD()
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	artifactTypeRequest := &pb.ArtifactByTypeRequest{ArtifactType: pb.ArtifactByTypeRequest_MODEL}
+	var names []string
+	pages := 0
+	options := registry.ListOptions{PageSize: 1}
+	for {
+		artifactsResponse, nextPageToken, err := workspace.ListArtifactsByType(artifactTypeRequest, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages++
+		for _, artifactData := range artifactsResponse.GetArtifacts() {
+			names = append(names, artifactData.GetName())
+		}
+		if nextPageToken == "" {
+			break
+		}
+		options.PageToken = nextPageToken
+	}
+	if fmt.Sprint(names) != "[MNIST FunctionComponent]" || pages != 4 {
+		t.Errorf("ListArtifactsByType = %v in %d pages, want [MNIST FunctionComponent] in 4 pages", names, pages)
+	}
+}
+
+func TestArtifactIteratorError(t *testing.T) {
+	mlmd := exampleMLMD()
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	iterator := workspace.IterateArtifacts(context.Background(), registry.ListOptions{PageSize: 3})
+	count := 0
+	for iterator.Next() {
+		count++
+		if count == 3 {
+			mlmd.SetError("GetArtifactsByContext", status.Error(codes.Unavailable, "connection refused"))
+		}
+	}
+	if count != 3 || !errors.Is(iterator.Err(), registry.ErrUnavailable) {
+		t.Errorf("iterated over %d artifacts with error %v, want 3 and ErrUnavailable", count, iterator.Err())
+	}
+}
+
+func TestGetArtifactsByTypeWorkspaceUsesWorkspaceContext(t *testing.T) {
+	mlmd := exampleMLMD()
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	artifactList, err := workspace.GetArtifactsByTypeWorkspace(&pb.ArtifactByTypeRequest{ArtifactType: pb.ArtifactByTypeRequest_DATASET})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(artifactList.GetArtifacts()) != 1 || artifactList.GetArtifacts()[0].GetArtifactType() != pb.ArtifactData_DATASET {
+		t.Errorf("GetArtifactsByTypeWorkspace(DATASET) = %v", artifactList.GetArtifacts())
+	}
+	if calls := mlmd.Calls("GetArtifactsByType"); calls != 0 {
+		t.Errorf("GetArtifactsByType called %d times, the artifacts of every workspace were fetched", calls)
+	}
+}
+
+func TestArtifactDataMetadata(t *testing.T) {
+	mlmd := exampleMLMD()
+	mlmd.SeedArtifact(mlmdtest.Artifact{
+		Type:       mlmdtest.ModelType,
+		Name:       "MNIST-tuned",
+		Workspace:  "workspace_1",
+		State:      pb.Artifact_LIVE,
+		Properties: map[string]*pb.Value{"training_framework": mlmdtest.StringValue("tensorflow")},
+		CustomProperties: map[string]*pb.Value{
+			"epochs":   mlmdtest.IntValue(10),
+			"accuracy": mlmdtest.DoubleValue(0.98),
+		},
+	})
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+
+	response, err := artifactStore.GetArtifactsByID(&pb.MLArtifact{Ids: []int64{6}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	artifactData := response.GetArtifacts()[0]
+
+	fields := artifactData.GetMetadata().GetFields()
+	if fields["training_framework"].GetStringValue() != "tensorflow" ||
+		fields["epochs"].GetNumberValue() != 10 ||
+		fields["accuracy"].GetNumberValue() != 0.98 ||
+		fields["__kf_workspace__"].GetStringValue() != "workspace_1" {
+		t.Errorf("metadata = %v", artifactData.GetMetadata())
+	}
+	if artifactData.GetState() != pb.ArtifactData_LIVE {
+		t.Errorf("state = %v, want LIVE", artifactData.GetState())
+	}
+	if artifactData.GetCreateTime() == nil || artifactData.GetLastUpdateTime().AsTime().Before(artifactData.GetCreateTime().AsTime()) {
+		t.Errorf("create time %v, last update time %v", artifactData.GetCreateTime(), artifactData.GetLastUpdateTime())
+	}
+}
+
+func TestArtifactTypeResolution(t *testing.T) {
+	mlmd := exampleMLMD()
+	for _, typeName := range []string{"system.Model", "acme.Tokenizer"} {
+		typeName := typeName
+		_, err := mlmd.PutArtifactType(context.Background(), &pb.PutArtifactTypeRequest{
+			ArtifactType: &pb.ArtifactType{Name: &typeName, Properties: map[string]pb.PropertyType{"name": pb.PropertyType_STRING}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	mlmd.SeedArtifact(mlmdtest.Artifact{Type: "system.Model", Name: "MNIST-v2", Workspace: "workspace_1"})
+	mlmd.SeedArtifact(mlmdtest.Artifact{Type: "acme.Tokenizer", Name: "sentencepiece", Workspace: "workspace_1"})
+
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	describe := func(artifactsResponse *pb.ArtifactsResponse) string {
+		var artifacts []string
+		for _, artifactData := range artifactsResponse.GetArtifacts() {
+			artifacts = append(artifacts, fmt.Sprintf("%s:%s:%s", artifactData.GetName(), artifactData.GetTypeName(), artifactData.GetArtifactType()))
+		}
+		return fmt.Sprint(artifacts)
+	}
+
+	models, err := workspace.GetArtifactsByTypeWorkspace(&pb.ArtifactByTypeRequest{ArtifactType: pb.ArtifactByTypeRequest_MODEL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := describe(models); got != "[MNIST:kubeflow.org/alpha/model:MODEL FunctionComponent:kubeflow.org/alpha/model:MODEL MNIST-v2:system.Model:MODEL]" {
+		t.Errorf("models = %s", got)
+	}
+
+	tokenizers, err := workspace.GetArtifactsByTypeWorkspace(&pb.ArtifactByTypeRequest{TypeName: "acme.Tokenizer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := describe(tokenizers); got != "[sentencepiece:acme.Tokenizer:OTHER]" {
+		t.Errorf("tokenizers = %s", got)
+	}
+
+	mappedStore, _ := registry.NewArtifactStore(
+		registry.WithClient(mlmd),
+		registry.WithArtifactTypeMapping(map[string]pb.ArtifactData_ArtifactType{"acme.Tokenizer": pb.ArtifactData_MODEL}),
+	)
+	mappedWorkspace, _ := mappedStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	models, err = mappedWorkspace.GetArtifactsByTypeWorkspace(&pb.ArtifactByTypeRequest{ArtifactType: pb.ArtifactByTypeRequest_MODEL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(models.GetArtifacts()) != 4 {
+		t.Errorf("models with custom mapping = %s", describe(models))
+	}
+
+	missing, err := workspace.GetArtifactsByTypeWorkspace(&pb.ArtifactByTypeRequest{TypeName: "system.Artifact"})
+	if err != nil || len(missing.GetArtifacts()) != 0 {
+		t.Errorf("unknown type name = %v, %v", missing, err)
+	}
+}
+
+func TestKFPv2AndTFXArtifactNames(t *testing.T) {
+	mlmd := exampleMLMD()
+	for _, typeName := range []string{"system.Model", "Model"} {
+		typeName := typeName
+		if _, err := mlmd.PutArtifactType(context.Background(), &pb.PutArtifactTypeRequest{ArtifactType: &pb.ArtifactType{Name: &typeName}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// TFX keeps the name in a custom property
+	mlmd.SeedArtifact(mlmdtest.Artifact{Type: "Model", Name: "fraud", Workspace: "workspace_1"})
+
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// KFP v2 keeps it in the name field of the artifact
+	systemModel, _ := artifactStore.Types().ArtifactTypeByName(context.Background(), "system.Model")
+	name, uri := "fraud", "gs://my-bucket/fraud"
+	response, err := mlmd.PutArtifacts(context.Background(), &pb.PutArtifactsRequest{
+		Artifacts: []*pb.Artifact{{TypeId: systemModel.Id, Name: &name, Uri: &uri}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = mlmd.PutAttributionsAndAssociations(context.Background(), &pb.PutAttributionsAndAssociationsRequest{
+		Attributions: []*pb.Attribution{{ContextId: &workspace.Id, ArtifactId: &response.ArtifactIds[0]}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	describe := func(artifacts []*pb.ArtifactData) string {
+		var described []string
+		for _, artifactData := range artifacts {
+			described = append(described, artifactData.GetName()+":"+artifactData.GetTypeName())
+		}
+		return fmt.Sprint(described)
+	}
+	matched, _, err := workspace.ListArtifacts(registry.ListOptions{}, registry.NameMatches("fraud"))
+	if err != nil || describe(matched.GetArtifacts()) != "[fraud:Model fraud:system.Model]" {
+		t.Errorf("artifacts named fraud = %s, %v", describe(matched.GetArtifacts()), err)
+	}
+	versions, err := workspace.ListModelVersions("fraud")
+	if err != nil || describe(versions) != "[fraud:Model fraud:system.Model]" {
+		t.Errorf("versions of fraud = %s, %v", describe(versions), err)
+	}
+}
+
+func TestTypeRegistry(t *testing.T) {
+	mlmd := exampleMLMD()
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	modelRequest := &pb.ArtifactByTypeRequest{ArtifactType: pb.ArtifactByTypeRequest_MODEL}
+
+	for i := 0; i < 3; i++ {
+		if _, err := workspace.GetArtifactsByTypeWorkspace(modelRequest); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls := mlmd.Calls("GetArtifactTypes"); calls != 1 {
+		t.Errorf("GetArtifactTypes called %d times, want 1", calls)
+	}
+
+	// An artifact of a type created by another client refreshes the cache
+	typeName := "acme.Tokenizer"
+	typeResponse, err := mlmd.PutArtifactType(context.Background(), &pb.PutArtifactTypeRequest{
+		ArtifactType: &pb.ArtifactType{Name: &typeName, Properties: map[string]pb.PropertyType{"name": pb.PropertyType_STRING}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mlmd.SeedArtifact(mlmdtest.Artifact{Type: typeName, Name: "sentencepiece", Workspace: "workspace_1"})
+	artifactList, _, err := workspace.ListArtifacts(registry.ListOptions{}, registry.NameMatches("sentencepiece"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(artifactList.GetArtifacts()) != 1 || artifactList.GetArtifacts()[0].GetTypeName() != typeName {
+		t.Errorf("artifacts = %v", artifactList.GetArtifacts())
+	}
+	if calls := mlmd.Calls("GetArtifactTypes"); calls != 2 {
+		t.Errorf("GetArtifactTypes called %d times after an unknown type, want 2", calls)
+	}
+
+	types := artifactStore.Types()
+	tokenizer, err := types.ArtifactType(context.Background(), typeResponse.GetTypeId())
+	if err != nil || tokenizer.GetName() != typeName || tokenizer.GetProperties()["name"] != pb.PropertyType_STRING {
+		t.Errorf("ArtifactType = %v, %v", tokenizer, err)
+	}
+	workspaceType, err := types.ContextTypeByName(context.Background(), mlmdtest.WorkspaceType)
+	if err != nil || workspaceType.GetName() != mlmdtest.WorkspaceType {
+		t.Errorf("ContextTypeByName = %v, %v", workspaceType, err)
+	}
+	if _, err := types.ExecutionTypeByName(context.Background(), "acme.Training"); !errors.Is(err, registry.ErrNotFound) {
+		t.Errorf("unknown execution type error = %v, want ErrNotFound", err)
+	}
+	if calls := mlmd.Calls("GetArtifactTypes"); calls != 2 {
+		t.Errorf("GetArtifactTypes called %d times for a known type, want 2", calls)
+	}
+
+	// An unknown type refreshes the cache once until it expires
+	for i := 0; i < 3; i++ {
+		if _, err := types.ArtifactType(context.Background(), 1000); !errors.Is(err, registry.ErrNotFound) {
+			t.Errorf("unknown artifact type error = %v, want ErrNotFound", err)
+		}
+	}
+	if calls := mlmd.Calls("GetArtifactTypes"); calls != 3 {
+		t.Errorf("GetArtifactTypes called %d times for an unknown type, want 3", calls)
+	}
+
+	// The cached types are not modified through the returned ones
+	tokenizer.Properties["name"] = pb.PropertyType_INT
+	if tokenizer, _ := types.ArtifactTypeByName(context.Background(), typeName); tokenizer.GetProperties()["name"] != pb.PropertyType_STRING {
+		t.Errorf("cached type was modified: %v", tokenizer)
+	}
+
+	types.Invalidate()
+	if _, err := types.ArtifactTypes(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if calls := mlmd.Calls("GetArtifactTypes"); calls != 4 {
+		t.Errorf("GetArtifactTypes called %d times after Invalidate, want 4", calls)
+	}
+
+	uncachedStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd), registry.WithTypeCacheTTL(0))
+	mlmd.ResetCalls()
+	for i := 0; i < 2; i++ {
+		if _, err := uncachedStore.Types().ArtifactTypes(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls := mlmd.Calls("GetArtifactTypes"); calls != 2 {
+		t.Errorf("GetArtifactTypes called %d times without caching, want 2", calls)
+	}
+}
+
+// blockingClient blocks GetArtifactTypes calls until unblock is closed.
+type blockingClient struct {
+	pb.MetadataStoreServiceClient
+	blocked chan struct{}
+	unblock chan struct{}
+}
+
+func (client *blockingClient) GetArtifactTypes(ctx context.Context, in *pb.GetArtifactTypesRequest, opts ...grpc.CallOption) (*pb.GetArtifactTypesResponse, error) {
+	close(client.blocked)
+	<-client.unblock
+	return client.MetadataStoreServiceClient.GetArtifactTypes(ctx, in, opts...)
+}
+
+func TestTypeRegistryFetchesWithoutLock(t *testing.T) {
+	client := &blockingClient{MetadataStoreServiceClient: exampleMLMD(), blocked: make(chan struct{}), unblock: make(chan struct{})}
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(client))
+	types := artifactStore.Types()
+
+	fetched := make(chan error)
+	go func() {
+		_, err := types.ArtifactTypes(context.Background())
+		fetched <- err
+	}()
+	<-client.blocked
+
+	// Other kinds of types are served while the artifact types are fetched
+	served := make(chan error)
+	go func() {
+		_, err := types.ContextTypeByName(context.Background(), mlmdtest.WorkspaceType)
+		served <- err
+	}()
+	select {
+	case err := <-served:
+		if err != nil {
+			t.Errorf("ContextTypeByName = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("ContextTypeByName blocked by the fetch of the artifact types")
+	}
+	close(client.unblock)
+	if err := <-fetched; err != nil {
+		t.Errorf("ArtifactTypes = %v", err)
+	}
+}
+
+func ExampleWorkspace_GetLatestModel() {
+	mlmd := exampleMLMD()
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	workspace, _ := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	for _, version := range []string{"1.10.0", "1.9.2", "1.10.0-rc.1"} {
+		workspace.RegisterModel(registry.ArtifactSpec{Name: "fraud", Version: version, Uri: "gcs://my-bucket/fraud/" + version})
+	}
+
+	newest, _ := workspace.GetLatestModel("fraud", registry.ByCreateTime)
+	highest, _ := workspace.GetLatestModel("fraud", registry.ByVersion)
+	fmt.Println(newest.GetVersion(), highest.GetVersion())
+	// Output: 1.10.0-rc.1 1.10.0
+}
+
+func TestModelVersions(t *testing.T) {
+	mlmd := exampleMLMD()
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, version := range []string{"v2.0.0", "2.0.0-beta.2", "2.0.0-beta.11", "2.0.0-alpha"} {
+		if _, err := workspace.RegisterModel(registry.ArtifactSpec{Name: "MNIST", Version: version}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	versions, err := workspace.ListModelVersions("MNIST")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, version := range versions {
+		names = append(names, version.GetVersion())
+	}
+	if got := fmt.Sprint(names); got != "[model_version_69389a49-b841-41a3-b1b2-15b3cb8c629e v2.0.0 2.0.0-beta.2 2.0.0-beta.11 2.0.0-alpha]" {
+		t.Errorf("versions = %s", got)
+	}
+
+	latest, err := workspace.GetLatestModel("MNIST", registry.ByVersion)
+	if err != nil || latest.GetVersion() != "v2.0.0" {
+		t.Errorf("latest by version = %v, %v", latest, err)
+	}
+
+	for requested, want := range map[string]int64{
+		"69389A49-B841-41A3-B1B2-15B3CB8C629E":               2,
+		"model_version_69389a49-b841-41a3-b1b2-15b3cb8c629e": 2,
+		"2.0.0":        versions[1].GetId(),
+		"2.0.0-beta.2": versions[2].GetId(),
+	} {
+		version, err := workspace.GetModelVersion("MNIST", requested)
+		if err != nil || version.GetId() != want {
+			t.Errorf("GetModelVersion(%s) = %v, %v, want ID %d", requested, version, err, want)
+		}
+	}
+
+	if _, err := workspace.GetModelVersion("MNIST", "3.0.0"); !errors.Is(err, registry.ErrNotFound) {
+		t.Errorf("unknown version error = %v, want ErrNotFound", err)
+	}
+	if _, err := workspace.GetLatestModel("mnist", registry.ByCreateTime); !errors.Is(err, registry.ErrNotFound) {
+		t.Errorf("unknown model error = %v, want ErrNotFound", err)
+	}
+	if _, err := workspace.ListModelVersions(""); !errors.Is(err, registry.ErrInvalidArgument) {
+		t.Errorf("empty name error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+func ExampleWorkspace_TransitionModelStage() {
+	mlmd := exampleMLMD()
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	workspace, _ := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+
+	workspace.TransitionModelStage(2, registry.StageTransitionSpec{
+		Stage:  registry.StageProduction,
+		User:   "alice",
+		Reason: "Best accuracy on the holdout set",
+	})
+
+	production, _ := workspace.GetModelByStage("MNIST", registry.StageProduction)
+	fmt.Println(production.GetId(), production.GetUri())
+	// Output: 2 gcs://my-bucket/mnist
+}
+
+func TestTransitionModelStage(t *testing.T) {
+	mlmd := exampleMLMD()
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	candidate, err := workspace.RegisterModel(registry.ArtifactSpec{Name: "MNIST", Version: "2.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []int64{2, candidate.GetId()} {
+		if _, err := workspace.TransitionModelStage(id, registry.StageTransitionSpec{Stage: registry.StageStaging, User: "ci"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	staging, err := workspace.GetModelByStage("MNIST", registry.StageStaging)
+	if err != nil || staging.GetId() != candidate.GetId() {
+		t.Errorf("latest staging model = %v, %v", staging, err)
+	}
+
+	if _, err := workspace.TransitionModelStage(2, registry.StageTransitionSpec{Stage: registry.StageProduction, User: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = workspace.TransitionModelStage(candidate.GetId(), registry.StageTransitionSpec{Stage: registry.StageProduction, User: "bob"})
+	if !errors.Is(err, registry.ErrConflict) {
+		t.Errorf("second production version error = %v, want ErrConflict", err)
+	}
+
+	promoted, err := workspace.TransitionModelStage(candidate.GetId(), registry.StageTransitionSpec{
+		Stage:           registry.StageProduction,
+		User:            "bob",
+		Reason:          "Retrained on fresh data",
+		ArchiveExisting: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stage := promoted.GetMetadata().GetFields()["stage"].GetStringValue(); stage != "Production" {
+		t.Errorf("promoted stage = %s", stage)
+	}
+	production, err := workspace.GetModelByStage("MNIST", registry.StageProduction)
+	if err != nil || production.GetId() != candidate.GetId() {
+		t.Errorf("production model = %v, %v", production, err)
+	}
+	archived, err := workspace.GetModelByStage("MNIST", registry.StageArchived)
+	if err != nil || archived.GetId() != 2 {
+		t.Errorf("archived model = %v, %v", archived, err)
+	}
+
+	history, err := workspace.GetStageHistory(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var transitions []string
+	for _, transition := range history {
+		transitions = append(transitions, fmt.Sprintf("%s->%s by %s", transition.From, transition.To, transition.User))
+	}
+	if got := fmt.Sprint(transitions); got != "[None->Staging by ci Staging->Production by alice Production->Archived by bob]" {
+		t.Errorf("history = %s", got)
+	}
+	if history[2].Time.IsZero() || history[2].Reason == "" {
+		t.Errorf("archive transition = %+v", history[2])
+	}
+
+	if _, err := workspace.TransitionModelStage(1, registry.StageTransitionSpec{Stage: registry.StageStaging}); !errors.Is(err, registry.ErrInvalidArgument) {
+		t.Errorf("dataset transition error = %v, want ErrInvalidArgument", err)
+	}
+	if _, err := workspace.TransitionModelStage(5, registry.StageTransitionSpec{Stage: registry.StageStaging}); !errors.Is(err, registry.ErrNotFound) {
+		t.Errorf("other workspace transition error = %v, want ErrNotFound", err)
+	}
+	if _, err := workspace.TransitionModelStage(2, registry.StageTransitionSpec{Stage: "Canary"}); !errors.Is(err, registry.ErrInvalidArgument) {
+		t.Errorf("unknown stage error = %v, want ErrInvalidArgument", err)
+	}
+}
+
+// racingClient runs a concurrent change right before the first PutArtifacts
+// call.
+type racingClient struct {
+	pb.MetadataStoreServiceClient
+	race func()
+}
+
+func (client *racingClient) PutArtifacts(ctx context.Context, in *pb.PutArtifactsRequest, opts ...grpc.CallOption) (*pb.PutArtifactsResponse, error) {
+	if race := client.race; race != nil {
+		client.race = nil
+		race()
+	}
+	return client.MetadataStoreServiceClient.PutArtifacts(ctx, in, opts...)
+}
+
+func TestTransitionModelStageConcurrently(t *testing.T) {
+	mlmd := exampleMLMD()
+	other, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	otherWorkspace, err := other.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _ := otherWorkspace.RegisterModel(registry.ArtifactSpec{Name: "MNIST", Version: "2.0.0"})
+	second, _ := otherWorkspace.RegisterModel(registry.ArtifactSpec{Name: "MNIST", Version: "3.0.0"})
+	if _, err := otherWorkspace.TransitionModelStage(2, registry.StageTransitionSpec{Stage: registry.StageProduction}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another client promotes the first candidate while the second one is
+	// promoted, the second promotion must archive it
+	client := &racingClient{MetadataStoreServiceClient: mlmd, race: func() {
+		if _, err := otherWorkspace.TransitionModelStage(first.GetId(), registry.StageTransitionSpec{Stage: registry.StageProduction, ArchiveExisting: true}); err != nil {
+			t.Error(err)
+		}
+	}}
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(client))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := workspace.TransitionModelStage(second.GetId(), registry.StageTransitionSpec{Stage: registry.StageProduction, ArchiveExisting: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	for id, want := range map[int64]string{2: "Archived", first.GetId(): "Archived", second.GetId(): "Production"} {
+		history, err := workspace.GetStageHistory(id)
+		if err != nil || len(history) == 0 || string(history[len(history)-1].To) != want {
+			t.Errorf("stage history of %d = %+v, %v, want %s last", id, history, err, want)
+		}
+	}
+	if history, _ := workspace.GetStageHistory(2); len(history) != 2 {
+		t.Errorf("stage history of 2 = %+v, want the archive by the other client only", history)
+	}
+}
+
+func TestAliasesOfPipelineArtifacts(t *testing.T) {
+	mlmd := exampleMLMD()
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An artifact logged by a pipeline is attributed to the workspace without
+	// the workspace property
+	modelType, _ := artifactStore.Types().ArtifactTypeByName(context.Background(), mlmdtest.ModelType)
+	response, err := mlmd.PutArtifacts(context.Background(), &pb.PutArtifactsRequest{Artifacts: []*pb.Artifact{{
+		TypeId:     modelType.Id,
+		Properties: map[string]*pb.Value{"name": mlmdtest.StringValue("fraud"), "version": mlmdtest.StringValue("1.0.0")},
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	artifactId := response.GetArtifactIds()[0]
+	_, err = mlmd.PutAttributionsAndAssociations(context.Background(), &pb.PutAttributionsAndAssociationsRequest{
+		Attributions: []*pb.Attribution{{ContextId: &workspace.Id, ArtifactId: &artifactId}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := workspace.SetAlias(artifactId, "champion"); err != nil {
+		t.Errorf("SetAlias of a pipeline artifact = %v", err)
+	}
+	if _, err := workspace.AddTags(artifactId, "validated"); err != nil {
+		t.Errorf("AddTags of a pipeline artifact = %v", err)
+	}
+
+	// The workspace property alone does not make an artifact part of the
+	// workspace
+	orphan := mlmd.SeedArtifact(mlmdtest.Artifact{
+		Type:             mlmdtest.ModelType,
+		Name:             "orphan",
+		CustomProperties: map[string]*pb.Value{"__kf_workspace__": mlmdtest.StringValue("workspace_1")},
+	})
+	if _, err := workspace.AddTags(orphan, "validated"); !errors.Is(err, registry.ErrNotFound) {
+		t.Errorf("AddTags of an unattributed artifact error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSetAliasConcurrently(t *testing.T) {
+	mlmd := exampleMLMD()
+	other, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	otherWorkspace, err := other.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _ := otherWorkspace.RegisterModel(registry.ArtifactSpec{Name: "MNIST", Version: "2.0.0"})
+	second, _ := otherWorkspace.RegisterModel(registry.ArtifactSpec{Name: "MNIST", Version: "3.0.0"})
+	if _, err := otherWorkspace.SetAlias(2, "champion"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another client moves the alias to the first candidate while it is moved
+	// to the second one, which must take it from the first
+	client := &racingClient{MetadataStoreServiceClient: mlmd, race: func() {
+		if _, err := otherWorkspace.SetAlias(first.GetId(), "champion"); err != nil {
+			t.Error(err)
+		}
+	}}
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(client))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := workspace.SetAlias(second.GetId(), "champion"); err != nil {
+		t.Fatal(err)
+	}
+
+	artifacts, err := workspace.GetArtifactsByWorkspace()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var holders []int64
+	for _, artifactData := range artifacts.GetArtifacts() {
+		if strings.Contains(artifactData.GetMetadata().GetFields()["aliases"].GetStringValue(), `"champion"`) {
+			holders = append(holders, artifactData.GetId())
+		}
+	}
+	if fmt.Sprint(holders) != fmt.Sprintf("[%d]", second.GetId()) {
+		t.Errorf("champion holders = %v, want artifact %d only", holders, second.GetId())
+	}
+}
+
+func TestStageOfTypesWithStageProperty(t *testing.T) {
+	mlmd := exampleMLMD()
+	typeName := "acme.Model"
+	_, err := mlmd.PutArtifactType(context.Background(), &pb.PutArtifactTypeRequest{
+		ArtifactType: &pb.ArtifactType{Name: &typeName, Properties: map[string]pb.PropertyType{"name": pb.PropertyType_STRING, "stage": pb.PropertyType_STRING}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := mlmd.SeedArtifact(mlmdtest.Artifact{
+		Type:       typeName,
+		Name:       "fraud",
+		Workspace:  "workspace_1",
+		Properties: map[string]*pb.Value{"stage": mlmdtest.StringValue("Production")},
+	})
+
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd), registry.WithArtifactTypeMapping(map[string]pb.ArtifactData_ArtifactType{typeName: pb.ArtifactData_MODEL}))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The stage property of the type is not the stage of the registry
+	if _, err := workspace.GetModelByStage("fraud", registry.StageProduction); !errors.Is(err, registry.ErrNotFound) {
+		t.Errorf("GetModelByStage(Production) error = %v, want ErrNotFound", err)
+	}
+	if _, err := workspace.TransitionModelStage(id, registry.StageTransitionSpec{Stage: registry.StageStaging}); err != nil {
+		t.Fatal(err)
+	}
+	staging, err := workspace.GetModelByStage("fraud", registry.StageStaging)
+	if err != nil || staging.GetId() != id {
+		t.Errorf("GetModelByStage(Staging) = %v, %v", staging, err)
+	}
+	history, err := workspace.GetStageHistory(id)
+	if err != nil || len(history) != 1 || history[0].From != registry.StageNone {
+		t.Errorf("stage history = %+v, %v", history, err)
+	}
+}
+
+func ExampleWorkspace_ResolveAlias() {
+	mlmd := exampleMLMD()
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	workspace, _ := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	workspace.SetAlias(2, "champion")
+
+	name, alias, _ := registry.ParseAliasReference("MNIST@champion")
+	artifactData, _ := workspace.ResolveAlias(name, alias)
+	fmt.Println(artifactData.GetId(), artifactData.GetUri())
+	// Output: 2 gcs://my-bucket/mnist
+}
+
+func TestAliasesAndTags(t *testing.T) {
+	mlmd := exampleMLMD()
+	artifactStore, _ := registry.NewArtifactStore(registry.WithClient(mlmd))
+	workspace, err := artifactStore.GetWorkspace(&pb.Workspace{Name: "workspace_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	challenger, err := workspace.RegisterModel(registry.ArtifactSpec{Name: "MNIST", Version: "2.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := workspace.SetAlias(2, "champion"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := workspace.SetAlias(challenger.GetId(), "challenger"); err != nil {
+		t.Fatal(err)
+	}
+	// The alias moves to the new version
+	if _, err := workspace.SetAlias(challenger.GetId(), "champion"); err != nil {
+		t.Fatal(err)
+	}
+	champion, err := workspace.ResolveAlias("MNIST", "champion")
+	if err != nil || champion.GetId() != challenger.GetId() {
+		t.Errorf("champion = %v, %v", champion, err)
+	}
+	if aliases := champion.GetMetadata().GetFields()["aliases"].GetStringValue(); aliases != `["challenger","champion"]` {
+		t.Errorf("aliases = %s", aliases)
+	}
+	previous, _, _ := workspace.ListArtifacts(registry.ListOptions{}, func(artifact *pb.Artifact) bool { return artifact.GetId() == 2 })
+	if aliases := previous.GetArtifacts()[0].GetMetadata().GetFields()["aliases"]; aliases != nil {
+		t.Errorf("previous champion aliases = %v", aliases)
+	}
+
+	if err := workspace.RemoveAlias("MNIST", "challenger"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := workspace.ResolveAlias("MNIST", "challenger"); !errors.Is(err, registry.ErrNotFound) {
+		t.Errorf("removed alias error = %v, want ErrNotFound", err)
+	}
+	if err := workspace.RemoveAlias("MNIST", "challenger"); !errors.Is(err, registry.ErrNotFound) {
+		t.Errorf("second removal error = %v, want ErrNotFound", err)
+	}
+	if _, err := workspace.SetAlias(2, "bad alias"); !errors.Is(err, registry.ErrInvalidArgument) {
+		t.Errorf("invalid alias error = %v, want ErrInvalidArgument", err)
+	}
+	if _, err := workspace.SetAlias(5, "champion"); !errors.Is(err, registry.ErrNotFound) {
+		t.Errorf("other workspace alias error = %v, want ErrNotFound", err)
+	}
+	if _, _, err := registry.ParseAliasReference("MNIST"); !errors.Is(err, registry.ErrInvalidArgument) {
+		t.Errorf("reference without alias error = %v, want ErrInvalidArgument", err)
+	}
+
+	for _, id := range []int64{1, 2} {
+		if _, err := workspace.AddTags(id, "mnist", "baseline"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := workspace.RemoveTags(1, "baseline"); err != nil {
+		t.Fatal(err)
+	}
+	tagged, err := workspace.ListByTag("baseline")
+	if err != nil || len(tagged.GetArtifacts()) != 1 || tagged.GetArtifacts()[0].GetId() != 2 {
+		t.Errorf("baseline artifacts = %v, %v", tagged, err)
+	}
+	datasets, err := workspace.GetArtifactsByTypeWorkspace(&pb.ArtifactByTypeRequest{ArtifactType: pb.ArtifactByTypeRequest_DATASET}, registry.HasTag("mnist"))
+	if err != nil || len(datasets.GetArtifacts()) != 1 || datasets.GetArtifacts()[0].GetId() != 1 {
+		t.Errorf("mnist datasets = %v, %v", datasets, err)
+	}
+	if _, err := workspace.AddTags(1, " "); !errors.Is(err, registry.ErrInvalidArgument) {
+		t.Errorf("empty tag error = %v, want ErrInvalidArgument", err)
+	}
 }
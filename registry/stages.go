@@ -0,0 +1,307 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Methods to move models through the stages of their lifecycle
+
+package artifact_registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Vernacular-ai/vcore/log"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+)
+
+// Stage of a model version in its lifecycle
+type Stage string
+
+const (
+	// Models without a stage are in StageNone
+	StageNone       Stage = "None"
+	StageStaging    Stage = "Staging"
+	StageProduction Stage = "Production"
+	StageArchived   Stage = "Archived"
+)
+
+const (
+	// Custom properties holding the stage of a model and the JSON encoded list
+	// of its StageTransition
+	STAGE_PROPERTY         = "stage"
+	STAGE_HISTORY_PROPERTY = "stage_history"
+)
+
+// StageTransitionSpec describes a stage transition of a model version
+type StageTransitionSpec struct {
+	Stage Stage
+	// Who requested the transition and why, recorded in the history
+	User   string
+	Reason string
+	// Archive the version of the model in Production, if any, when moving
+	// this one to Production. The transition fails otherwise.
+	ArchiveExisting bool
+}
+
+// StageTransition is an entry of the stage history of a model version
+type StageTransition struct {
+	From   Stage     `json:"from"`
+	To     Stage     `json:"to"`
+	User   string    `json:"user,omitempty"`
+	Reason string    `json:"reason,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// TransitionModelStage moves a model version of this workspace to another
+// stage, recording the transition in its history. At most one version of a
+// model is in Production: moving a version to Production fails with
+// ErrConflict while another one is, unless ArchiveExisting is set. Moving a
+// version to its current stage changes nothing.
+//
+// The versions are only written if MLMD did not update them since they were
+// read, the transition is retried otherwise. The Production rule is still
+// best-effort: two versions promoted concurrently while none is in
+// Production may both end up there, GetModelByStage then returns the most
+// recently created one.
+func (workspace Workspace) TransitionModelStage(artifactId int64, spec StageTransitionSpec) (*pb.ArtifactData, error) {
+	return workspace.TransitionModelStageWithContext(context.Background(), artifactId, spec)
+}
+
+// TransitionModelStageWithContext is TransitionModelStage using the provided
+// context for deadlines and cancellation.
+func (workspace Workspace) TransitionModelStageWithContext(ctx context.Context, artifactId int64, spec StageTransitionSpec) (*pb.ArtifactData, error) {
+	var artifactData *pb.ArtifactData
+
+	if !validStage(spec.Stage) {
+		return artifactData, newError("TransitionModelStage", ErrInvalidArgument, "unknown stage %q", spec.Stage)
+	}
+
+	client, err := workspace.artifactStore.metadataClient()
+	if err != nil {
+		return artifactData, wrapError("TransitionModelStage", err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		artifactData, err = workspace.transitionModelStage(ctx, client, artifactId, spec)
+		if !errors.Is(err, errUpdatedConcurrently) || attempt == updateAttempts {
+			break
+		}
+		log.Debugf("Artifact %d was updated concurrently, retrying its move to stage %s", artifactId, spec.Stage)
+	}
+	if err != nil {
+		return artifactData, wrapError("TransitionModelStage", err)
+	}
+	return artifactData, nil
+}
+
+// transitionModelStage reads the versions to move and writes them if they
+// were not updated in between, failing with errUpdatedConcurrently otherwise.
+func (workspace Workspace) transitionModelStage(ctx context.Context, client pb.MetadataStoreServiceClient, artifactId int64, spec StageTransitionSpec) (*pb.ArtifactData, error) {
+	model, err := workspace.fetchModel(ctx, client, artifactId)
+	if err != nil {
+		return nil, err
+	}
+	if artifactStage(model) == spec.Stage {
+		artifactList, err := workspace.artifactStore.prepareArtifactsList(ctx, client, []*pb.Artifact{model})
+		if err != nil {
+			return nil, err
+		}
+		return artifactList[0], nil
+	}
+
+	now := time.Now().UTC()
+	updated := []*pb.Artifact{model}
+	if spec.Stage == StageProduction {
+		production, err := workspace.modelsInStage(ctx, artifactName(model), StageProduction)
+		if err != nil {
+			return nil, err
+		}
+		var existingIds []int64
+		for _, version := range production {
+			if version.GetId() != artifactId {
+				existingIds = append(existingIds, version.GetId())
+			}
+		}
+
+		if len(existingIds) > 0 && !spec.ArchiveExisting {
+			return nil, newError("transitionModelStage", ErrConflict, "model %s already has version %d in Production", artifactName(model), existingIds[0])
+		}
+		if len(existingIds) > 0 {
+			response, err := client.GetArtifactsByID(ctx, &pb.GetArtifactsByIDRequest{ArtifactIds: existingIds})
+			if err != nil {
+				log.Debugf("Failed to fetch artifacts %v: %v", existingIds, err)
+				return nil, err
+			}
+			for _, existing := range response.GetArtifacts() {
+				archive := StageTransition{From: StageProduction, To: StageArchived, User: spec.User, Time: now,
+					Reason: "Archived when promoting version " + artifactVersion(model)}
+				if err := setArtifactStage(existing, archive); err != nil {
+					return nil, err
+				}
+				updated = append(updated, existing)
+			}
+		}
+	}
+
+	transition := StageTransition{From: artifactStage(model), To: spec.Stage, User: spec.User, Reason: spec.Reason, Time: now}
+	if err := setArtifactStage(model, transition); err != nil {
+		return nil, err
+	}
+
+	// A single call so that the archived versions and the promoted one change
+	// together, and only if none of them changed since it was read
+	if err := putArtifactsIfUnchanged(ctx, client, updated); err != nil {
+		log.Debugf("Failed to move artifact %d to stage %s: %v", artifactId, spec.Stage, err)
+		return nil, err
+	}
+	log.Debugf("Moved artifact %d from stage %s to %s", artifactId, transition.From, transition.To)
+
+	artifactList, err := workspace.artifactStore.fetchArtifacts(ctx, client, []int64{artifactId})
+	if err != nil {
+		return nil, err
+	}
+	return artifactList[0], nil
+}
+
+// GetModelByStage returns the version of the named model in a stage, the most
+// recently created one if several versions are in that stage, then the one
+// of the highest ID.
+func (workspace Workspace) GetModelByStage(name string, stage Stage) (*pb.ArtifactData, error) {
+	return workspace.GetModelByStageWithContext(context.Background(), name, stage)
+}
+
+// GetModelByStageWithContext is GetModelByStage using the provided context for
+// deadlines and cancellation.
+func (workspace Workspace) GetModelByStageWithContext(ctx context.Context, name string, stage Stage) (*pb.ArtifactData, error) {
+	var artifactData *pb.ArtifactData
+
+	if !validStage(stage) {
+		return artifactData, newError("GetModelByStage", ErrInvalidArgument, "unknown stage %q", stage)
+	}
+
+	versions, err := workspace.modelsInStage(ctx, name, stage)
+	if err != nil {
+		return artifactData, wrapError("GetModelByStage", err)
+	}
+	if len(versions) == 0 {
+		return artifactData, newError("GetModelByStage", ErrNotFound, "model %s has no version in stage %s", name, stage)
+	}
+
+	return versions[len(versions)-1], nil
+}
+
+// GetStageHistory returns the stage transitions of a model version of this
+// workspace, oldest first.
+func (workspace Workspace) GetStageHistory(artifactId int64) ([]StageTransition, error) {
+	return workspace.GetStageHistoryWithContext(context.Background(), artifactId)
+}
+
+// GetStageHistoryWithContext is GetStageHistory using the provided context for
+// deadlines and cancellation.
+func (workspace Workspace) GetStageHistoryWithContext(ctx context.Context, artifactId int64) ([]StageTransition, error) {
+	client, err := workspace.artifactStore.metadataClient()
+	if err != nil {
+		return nil, wrapError("GetStageHistory", err)
+	}
+
+	model, err := workspace.fetchModel(ctx, client, artifactId)
+	if err != nil {
+		return nil, wrapError("GetStageHistory", err)
+	}
+
+	history, err := stageHistory(model)
+	if err != nil {
+		return nil, wrapError("GetStageHistory", err)
+	}
+	return history, nil
+}
+
+// fetchModel fetches a model artifact of the workspace.
+func (workspace Workspace) fetchModel(ctx context.Context, client pb.MetadataStoreServiceClient, artifactId int64) (*pb.Artifact, error) {
+	model, err := workspace.fetchArtifact(ctx, client, artifactId)
+	if err != nil {
+		return nil, err
+	}
+
+	artifactType, err := workspace.artifactStore.typeRegistry(client).ArtifactType(ctx, model.GetTypeId())
+	if err != nil {
+		return nil, err
+	}
+	if workspace.artifactStore.artifactDataType(artifactType.GetName()) != pb.ArtifactData_MODEL {
+		return nil, newError("fetchModel", ErrInvalidArgument, "artifact %d is a %s, not a model", artifactId, artifactType.GetName())
+	}
+
+	return model, nil
+}
+
+// modelsInStage returns the versions of the named model in a stage, oldest
+// first.
+func (workspace Workspace) modelsInStage(ctx context.Context, name string, stage Stage) ([]*pb.ArtifactData, error) {
+	return workspace.modelVersions(ctx, name, func(artifact *pb.Artifact) bool {
+		return artifactStage(artifact) == stage
+	})
+}
+
+func validStage(stage Stage) bool {
+	switch stage {
+	case StageNone, StageStaging, StageProduction, StageArchived:
+		return true
+	}
+	return false
+}
+
+// artifactStage returns the stage of an artifact, StageNone if it was never
+// moved. The stage is always a custom property, written by setArtifactStage,
+// even if the type of the artifact has a stage property.
+func artifactStage(artifact *pb.Artifact) Stage {
+	if stage := artifact.GetCustomProperties()[STAGE_PROPERTY].GetStringValue(); stage != "" {
+		return Stage(stage)
+	}
+	return StageNone
+}
+
+func stageHistory(artifact *pb.Artifact) ([]StageTransition, error) {
+	var history []StageTransition
+
+	encoded := artifact.GetCustomProperties()[STAGE_HISTORY_PROPERTY].GetStringValue()
+	if encoded == "" {
+		return history, nil
+	}
+	if err := json.Unmarshal([]byte(encoded), &history); err != nil {
+		log.Debugf("Failed to decode the stage history of artifact %d: %v", artifact.GetId(), err)
+		return nil, err
+	}
+	return history, nil
+}
+
+// setArtifactStage moves an artifact to the stage of a transition and appends
+// the transition to its history.
+func setArtifactStage(artifact *pb.Artifact, transition StageTransition) error {
+	history, err := stageHistory(artifact)
+	if err != nil {
+		return err
+	}
+	encoded, err := json.Marshal(append(history, transition))
+	if err != nil {
+		return err
+	}
+
+	if artifact.CustomProperties == nil {
+		artifact.CustomProperties = map[string]*pb.Value{}
+	}
+	artifact.CustomProperties[STAGE_PROPERTY] = stringValue(string(transition.To))
+	artifact.CustomProperties[STAGE_HISTORY_PROPERTY] = stringValue(string(encoded))
+	return nil
+}
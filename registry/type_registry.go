@@ -0,0 +1,362 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Cache of the MLMD types
+
+package artifact_registry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Vernacular-ai/vcore/log"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+)
+
+// Time the types are cached for, unless configured otherwise with
+// WithTypeCacheTTL
+const defaultTypeCacheTTL = 10 * time.Minute
+
+// TypeRegistry caches the artifact, execution and context types of MLMD.
+//
+// Each kind of type is fetched at once the first time one is needed and kept
+// for the TTL of the cache. Looking up an unknown type refreshes the cache
+// early, so types created in the meantime are found, but only once per type
+// until the cache expires. The returned types are copies which callers may
+// modify. It is shared by an MLArtifactStore and its workspaces and is safe
+// for concurrent use.
+type TypeRegistry struct {
+	client pb.MetadataStoreServiceClient
+	ttl    time.Duration
+
+	mu             sync.Mutex
+	artifactTypes  typeCache
+	executionTypes typeCache
+	contextTypes   typeCache
+}
+
+// typeCache holds the types of one kind.
+type typeCache struct {
+	loaded *loadedTypes
+	// Unknown types already looked up, which do not refresh the cache again
+	// before it expires
+	misses map[string]bool
+}
+
+// loadedTypes are the types of one kind fetched at once. They are replaced as
+// a whole and never modified, so they may be read without the lock.
+type loadedTypes struct {
+	loadedAt time.Time
+	types    []proto.Message
+	byID     map[int64]proto.Message
+	byName   map[string]proto.Message
+}
+
+// mlmdType is implemented by the artifact, execution and context types.
+type mlmdType interface {
+	proto.Message
+	GetId() int64
+	GetName() string
+}
+
+func newTypeRegistry(client pb.MetadataStoreServiceClient, ttl time.Duration) *TypeRegistry {
+	return &TypeRegistry{client: client, ttl: ttl}
+}
+
+// Types returns the type registry of the store.
+func (artifactStore MLArtifactStore) Types() *TypeRegistry {
+	return artifactStore.types
+}
+
+// typeRegistry returns the type registry of the store, or an uncached one for
+// stores not created by NewArtifactStore.
+func (artifactStore MLArtifactStore) typeRegistry(client pb.MetadataStoreServiceClient) *TypeRegistry {
+	if artifactStore.types != nil {
+		return artifactStore.types
+	}
+	return newTypeRegistry(client, 0)
+}
+
+// Invalidate drops the cached types, they are fetched again on the next
+// lookup.
+func (types *TypeRegistry) Invalidate() {
+	types.mu.Lock()
+	defer types.mu.Unlock()
+
+	types.artifactTypes = typeCache{}
+	types.executionTypes = typeCache{}
+	types.contextTypes = typeCache{}
+}
+
+// ArtifactTypes returns all the artifact types ordered by ID.
+func (types *TypeRegistry) ArtifactTypes(ctx context.Context) ([]*pb.ArtifactType, error) {
+	cached, err := types.list(ctx, &types.artifactTypes, types.loadArtifactTypes)
+	if err != nil {
+		return nil, wrapError("ArtifactTypes", err)
+	}
+
+	artifactTypes := make([]*pb.ArtifactType, len(cached))
+	for i, artifactType := range cached {
+		artifactTypes[i] = artifactType.(*pb.ArtifactType)
+	}
+	return artifactTypes, nil
+}
+
+// ArtifactType returns the artifact type with the given ID.
+func (types *TypeRegistry) ArtifactType(ctx context.Context, typeId int64) (*pb.ArtifactType, error) {
+	artifactType, err := types.lookup(ctx, &types.artifactTypes, types.loadArtifactTypes, typeId, "")
+	if err != nil {
+		return nil, wrapError("ArtifactType", err)
+	}
+	return artifactType.(*pb.ArtifactType), nil
+}
+
+// ArtifactTypeByName returns the named artifact type.
+func (types *TypeRegistry) ArtifactTypeByName(ctx context.Context, typeName string) (*pb.ArtifactType, error) {
+	artifactType, err := types.lookup(ctx, &types.artifactTypes, types.loadArtifactTypes, 0, typeName)
+	if err != nil {
+		return nil, wrapError("ArtifactTypeByName", err)
+	}
+	return artifactType.(*pb.ArtifactType), nil
+}
+
+// ExecutionTypes returns all the execution types ordered by ID.
+func (types *TypeRegistry) ExecutionTypes(ctx context.Context) ([]*pb.ExecutionType, error) {
+	cached, err := types.list(ctx, &types.executionTypes, types.loadExecutionTypes)
+	if err != nil {
+		return nil, wrapError("ExecutionTypes", err)
+	}
+
+	executionTypes := make([]*pb.ExecutionType, len(cached))
+	for i, executionType := range cached {
+		executionTypes[i] = executionType.(*pb.ExecutionType)
+	}
+	return executionTypes, nil
+}
+
+// ExecutionType returns the execution type with the given ID.
+func (types *TypeRegistry) ExecutionType(ctx context.Context, typeId int64) (*pb.ExecutionType, error) {
+	executionType, err := types.lookup(ctx, &types.executionTypes, types.loadExecutionTypes, typeId, "")
+	if err != nil {
+		return nil, wrapError("ExecutionType", err)
+	}
+	return executionType.(*pb.ExecutionType), nil
+}
+
+// ExecutionTypeByName returns the named execution type.
+func (types *TypeRegistry) ExecutionTypeByName(ctx context.Context, typeName string) (*pb.ExecutionType, error) {
+	executionType, err := types.lookup(ctx, &types.executionTypes, types.loadExecutionTypes, 0, typeName)
+	if err != nil {
+		return nil, wrapError("ExecutionTypeByName", err)
+	}
+	return executionType.(*pb.ExecutionType), nil
+}
+
+// ContextTypes returns all the context types ordered by ID.
+func (types *TypeRegistry) ContextTypes(ctx context.Context) ([]*pb.ContextType, error) {
+	cached, err := types.list(ctx, &types.contextTypes, types.loadContextTypes)
+	if err != nil {
+		return nil, wrapError("ContextTypes", err)
+	}
+
+	contextTypes := make([]*pb.ContextType, len(cached))
+	for i, contextType := range cached {
+		contextTypes[i] = contextType.(*pb.ContextType)
+	}
+	return contextTypes, nil
+}
+
+// ContextType returns the context type with the given ID.
+func (types *TypeRegistry) ContextType(ctx context.Context, typeId int64) (*pb.ContextType, error) {
+	contextType, err := types.lookup(ctx, &types.contextTypes, types.loadContextTypes, typeId, "")
+	if err != nil {
+		return nil, wrapError("ContextType", err)
+	}
+	return contextType.(*pb.ContextType), nil
+}
+
+// ContextTypeByName returns the named context type.
+func (types *TypeRegistry) ContextTypeByName(ctx context.Context, typeName string) (*pb.ContextType, error) {
+	contextType, err := types.lookup(ctx, &types.contextTypes, types.loadContextTypes, 0, typeName)
+	if err != nil {
+		return nil, wrapError("ContextTypeByName", err)
+	}
+	return contextType.(*pb.ContextType), nil
+}
+
+// list returns copies of the cached types, fetching them if the cache
+// expired.
+func (types *TypeRegistry) list(ctx context.Context, cache *typeCache, load func(context.Context) ([]proto.Message, error)) ([]proto.Message, error) {
+	loaded, _, err := types.refresh(ctx, cache, load, false)
+	if err != nil {
+		return nil, err
+	}
+
+	copies := make([]proto.Message, len(loaded.types))
+	for i, loadedType := range loaded.types {
+		copies[i] = proto.Clone(loadedType)
+	}
+	return copies, nil
+}
+
+// lookup returns a copy of a type by ID, or by name if typeId is zero. An
+// unknown type refreshes the cache unless it was just fetched or the type was
+// already missing since the cache was last fetched.
+func (types *TypeRegistry) lookup(ctx context.Context, cache *typeCache, load func(context.Context) ([]proto.Message, error), typeId int64, typeName string) (proto.Message, error) {
+	find := func(loaded *loadedTypes) proto.Message {
+		if typeId != 0 {
+			return loaded.byID[typeId]
+		}
+		return loaded.byName[typeName]
+	}
+	missKey := "name:" + typeName
+	if typeId != 0 {
+		missKey = fmt.Sprintf("id:%d", typeId)
+	}
+
+	loaded, fetched, err := types.refresh(ctx, cache, load, false)
+	if err != nil {
+		return nil, err
+	}
+	found := find(loaded)
+	if found == nil && !fetched && !types.missed(cache, missKey) {
+		if loaded, fetched, err = types.refresh(ctx, cache, load, true); err != nil {
+			return nil, err
+		}
+		found = find(loaded)
+	}
+	if found != nil {
+		return proto.Clone(found), nil
+	}
+
+	if fetched {
+		types.mu.Lock()
+		if cache.misses == nil {
+			cache.misses = map[string]bool{}
+		}
+		cache.misses[missKey] = true
+		types.mu.Unlock()
+	}
+	if typeId != 0 {
+		return nil, newError("lookup", ErrNotFound, "type %d does not exist", typeId)
+	}
+	return nil, newError("lookup", ErrNotFound, "type %s does not exist", typeName)
+}
+
+// missed reports whether a type was missing since the cache was last fetched.
+func (types *TypeRegistry) missed(cache *typeCache, missKey string) bool {
+	types.mu.Lock()
+	defer types.mu.Unlock()
+
+	return cache.misses[missKey]
+}
+
+// refresh returns the cached types, fetching them if the cache expired or
+// force is set, along with whether they were fetched. The lock is not held
+// while fetching so that lookups of other kinds are not blocked by MLMD.
+func (types *TypeRegistry) refresh(ctx context.Context, cache *typeCache, load func(context.Context) ([]proto.Message, error), force bool) (*loadedTypes, bool, error) {
+	types.mu.Lock()
+	current := cache.loaded
+	types.mu.Unlock()
+	expired := current == nil || time.Since(current.loadedAt) >= types.ttl
+	if !force && !expired {
+		return current, false, nil
+	}
+
+	fetched, err := load(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	sort.Slice(fetched, func(i, j int) bool {
+		return fetched[i].(mlmdType).GetId() < fetched[j].(mlmdType).GetId()
+	})
+	loaded := &loadedTypes{
+		loadedAt: time.Now(),
+		types:    fetched,
+		byID:     make(map[int64]proto.Message, len(fetched)),
+		byName:   make(map[string]proto.Message, len(fetched)),
+	}
+	for _, fetchedType := range fetched {
+		loaded.byID[fetchedType.(mlmdType).GetId()] = fetchedType
+		loaded.byName[fetchedType.(mlmdType).GetName()] = fetchedType
+	}
+
+	types.mu.Lock()
+	defer types.mu.Unlock()
+	cache.loaded = loaded
+	// The misses are remembered until the cache expires
+	if expired {
+		cache.misses = nil
+	}
+	return loaded, true, nil
+}
+
+func (types *TypeRegistry) loadArtifactTypes(ctx context.Context) ([]proto.Message, error) {
+	if types.client == nil {
+		return nil, errNotConnected
+	}
+
+	response, err := types.client.GetArtifactTypes(ctx, &pb.GetArtifactTypesRequest{})
+	if err != nil {
+		log.Debugf("Failed to fetch artifact types: %v", err)
+		return nil, wrapError("GetArtifactTypes", err)
+	}
+
+	var loaded []proto.Message
+	for _, artifactType := range response.GetArtifactTypes() {
+		loaded = append(loaded, artifactType)
+	}
+	return loaded, nil
+}
+
+func (types *TypeRegistry) loadExecutionTypes(ctx context.Context) ([]proto.Message, error) {
+	if types.client == nil {
+		return nil, errNotConnected
+	}
+
+	response, err := types.client.GetExecutionTypes(ctx, &pb.GetExecutionTypesRequest{})
+	if err != nil {
+		log.Debugf("Failed to fetch execution types: %v", err)
+		return nil, wrapError("GetExecutionTypes", err)
+	}
+
+	var loaded []proto.Message
+	for _, executionType := range response.GetExecutionTypes() {
+		loaded = append(loaded, executionType)
+	}
+	return loaded, nil
+}
+
+func (types *TypeRegistry) loadContextTypes(ctx context.Context) ([]proto.Message, error) {
+	if types.client == nil {
+		return nil, errNotConnected
+	}
+
+	response, err := types.client.GetContextTypes(ctx, &pb.GetContextTypesRequest{})
+	if err != nil {
+		log.Debugf("Failed to fetch context types: %v", err)
+		return nil, wrapError("GetContextTypes", err)
+	}
+
+	var loaded []proto.Message
+	for _, contextType := range response.GetContextTypes() {
+		loaded = append(loaded, contextType)
+	}
+	return loaded, nil
+}
@@ -0,0 +1,136 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Artifact type resolution
+
+package artifact_registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Vernacular-ai/vcore/log"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+)
+
+// DefaultArtifactTypeMapping returns the artifact types known to map to the
+// ArtifactData types: Kubeflow v1, KFP v2 and TFX types. Types missing from
+// the mapping are OTHER.
+func DefaultArtifactTypeMapping() map[string]pb.ArtifactData_ArtifactType {
+	return map[string]pb.ArtifactData_ArtifactType{
+		// Kubeflow v1
+		MODEL_ARTIFACT_TYPE_NAME:   pb.ArtifactData_MODEL,
+		DATASET_ARTIFACT_TYPE_NAME: pb.ArtifactData_DATASET,
+		METRICS_ARTIFACT_TYPE_NAME: pb.ArtifactData_METRICS,
+
+		// KFP v2
+		"system.Model":                       pb.ArtifactData_MODEL,
+		"system.Dataset":                     pb.ArtifactData_DATASET,
+		"system.Metrics":                     pb.ArtifactData_METRICS,
+		"system.ClassificationMetrics":       pb.ArtifactData_METRICS,
+		"system.SlicedClassificationMetrics": pb.ArtifactData_METRICS,
+
+		// TFX
+		"Model":           pb.ArtifactData_MODEL,
+		"Examples":        pb.ArtifactData_DATASET,
+		"ModelEvaluation": pb.ArtifactData_METRICS,
+	}
+}
+
+// artifactDataType returns the ArtifactData type of an MLMD artifact type.
+func (artifactStore MLArtifactStore) artifactDataType(typeName string) pb.ArtifactData_ArtifactType {
+	typeMapping := artifactStore.typeMapping
+	if typeMapping == nil {
+		typeMapping = DefaultArtifactTypeMapping()
+	}
+
+	if artifactType, ok := typeMapping[typeName]; ok {
+		return artifactType
+	}
+	return pb.ArtifactData_OTHER
+}
+
+// requestedTypeNames returns the names of the MLMD artifact types selected by
+// a request: its type name if set, otherwise every type mapped to its
+// artifact type.
+func (artifactStore MLArtifactStore) requestedTypeNames(artifactTypeRequest *pb.ArtifactByTypeRequest) ([]string, error) {
+	if artifactTypeRequest.GetTypeName() != "" {
+		return []string{artifactTypeRequest.GetTypeName()}, nil
+	}
+
+	var artifactType pb.ArtifactData_ArtifactType
+	switch artifactTypeRequest.GetArtifactType() {
+	case pb.ArtifactByTypeRequest_MODEL:
+		artifactType = pb.ArtifactData_MODEL
+	case pb.ArtifactByTypeRequest_DATASET:
+		artifactType = pb.ArtifactData_DATASET
+	case pb.ArtifactByTypeRequest_METRICS:
+		artifactType = pb.ArtifactData_METRICS
+	default:
+		log.Debugf("Artifact type %s does not exist", artifactTypeRequest.GetArtifactType())
+		return nil, fmt.Errorf("artifact type %s does not exist: %w", artifactTypeRequest.GetArtifactType(), ErrInvalidArgument)
+	}
+
+	typeMapping := artifactStore.typeMapping
+	if typeMapping == nil {
+		typeMapping = DefaultArtifactTypeMapping()
+	}
+
+	var typeNames []string
+	for typeName, mappedType := range typeMapping {
+		if mappedType == artifactType {
+			typeNames = append(typeNames, typeName)
+		}
+	}
+	return typeNames, nil
+}
+
+// artifactTypeIds returns the IDs of the named artifact types, ignoring the
+// types MLMD does not know. The types are read from the type registry, types
+// created by other clients since it was refreshed are only found when looked up
+// alone.
+func (artifactStore MLArtifactStore) artifactTypeIds(ctx context.Context, client pb.MetadataStoreServiceClient, typeNames []string) (map[int64]bool, error) {
+	types := artifactStore.typeRegistry(client)
+	typeIds := make(map[int64]bool)
+
+	if len(typeNames) == 1 {
+		artifactType, err := types.ArtifactTypeByName(ctx, typeNames[0])
+		if errors.Is(err, ErrNotFound) {
+			return typeIds, nil
+		}
+		if err != nil {
+			log.Debugf("Failed to fetch artifact type %s: %v", typeNames[0], err)
+			return nil, err
+		}
+		typeIds[artifactType.GetId()] = true
+		return typeIds, nil
+	}
+
+	artifactTypes, err := types.ArtifactTypes(ctx)
+	if err != nil {
+		log.Debugf("Failed to fetch artifact types: %v", err)
+		return nil, err
+	}
+	requested := make(map[string]bool, len(typeNames))
+	for _, typeName := range typeNames {
+		requested[typeName] = true
+	}
+	for _, artifactType := range artifactTypes {
+		if requested[artifactType.GetName()] {
+			typeIds[artifactType.GetId()] = true
+		}
+	}
+	return typeIds, nil
+}
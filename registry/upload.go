@@ -0,0 +1,192 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Methods to upload and register artifacts
+
+package artifact_registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Vernacular-ai/vcore/log"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+)
+
+// UploadSpec describes an artifact to upload and register in a workspace.
+// Uri is the destination of the upload, e.g. gcs://my-bucket/models/mnist/2.0.0.
+type UploadSpec struct {
+	// MODEL by default
+	ArtifactType pb.ArtifactData_ArtifactType
+	ArtifactSpec
+}
+
+// localFile is a file to upload
+type localFile struct {
+	path string
+	// Slash separated path relative to the uploaded directory, empty for a
+	// single file
+	relative string
+	size     int64
+}
+
+// UploadAndRegister uploads a local file or directory to the blob store of
+// the scheme of spec.Uri and registers it in this workspace along with the
+// digest and size of its content, see WithBlobStore.
+//
+// The artifact is registered as LIVE once the upload succeeded, so that
+// readers never see a partial upload. If the upload or the registration
+// fails, the uploaded files are deleted. Uploading to a URI holding objects
+// fails with ErrConflict.
+func (workspace Workspace) UploadAndRegister(source string, spec UploadSpec) (*pb.ArtifactData, error) {
+	return workspace.UploadAndRegisterWithContext(context.Background(), source, spec)
+}
+
+// UploadAndRegisterWithContext is UploadAndRegister using the provided context
+// for deadlines and cancellation.
+func (workspace Workspace) UploadAndRegisterWithContext(ctx context.Context, source string, spec UploadSpec) (*pb.ArtifactData, error) {
+	var artifactData *pb.ArtifactData
+
+	if spec.Name == "" || spec.Uri == "" {
+		return artifactData, newError("UploadAndRegister", ErrInvalidArgument, "artifact name and URI are required")
+	}
+	typeName, ok := artifactTypeName(spec.ArtifactType)
+	if !ok {
+		return artifactData, newError("UploadAndRegister", ErrInvalidArgument, "artifact type %s can not be created", spec.ArtifactType)
+	}
+
+	files, err := localFiles(source)
+	if err != nil {
+		return artifactData, wrapError("UploadAndRegister", err)
+	}
+	writer, err := workspace.artifactStore.BlobStores().Writer(spec.Uri)
+	if err != nil {
+		return artifactData, wrapError("UploadAndRegister", err)
+	}
+	if err := checkDestinationFree(ctx, writer, spec.Uri); err != nil {
+		return artifactData, wrapError("UploadAndRegister", err)
+	}
+
+	digests, err := uploadFiles(ctx, writer, files, spec.Uri)
+	if err == nil {
+		spec.Digest, spec.Size = payloadDigest(digests)
+		artifactData, err = workspace.registerArtifact(ctx, typeName, spec.ArtifactSpec, pb.Artifact_LIVE)
+	}
+	if err != nil {
+		workspace.deleteUploaded(writer, files, spec.Uri)
+		return nil, wrapError("UploadAndRegister", err)
+	}
+	log.Debugf("Uploaded %s to %s as artifact %d", source, spec.Uri, artifactData.GetId())
+
+	return artifactData, nil
+}
+
+// localFiles lists the files to upload from a file or directory.
+func localFiles(source string) ([]localFile, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, localError("localFiles", source, err)
+	}
+	if !info.IsDir() {
+		return []localFile{{path: source, size: info.Size()}}, nil
+	}
+
+	var files []localFile
+	err = filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		relative, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, localFile{path: path, relative: filepath.ToSlash(relative), size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, localError("localFiles", source, err)
+	}
+	if len(files) == 0 {
+		return nil, newError("localFiles", ErrInvalidArgument, "%s holds no file", source)
+	}
+	return files, nil
+}
+
+// checkDestinationFree fails if objects exist at uri or under it.
+func checkDestinationFree(ctx context.Context, store BlobStore, uri string) error {
+	if _, err := store.Stat(ctx, uri); err == nil {
+		return newError("checkDestinationFree", ErrConflict, "%s already exists", uri)
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	blobs, err := store.List(ctx, uri)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if len(blobs) > 0 {
+		return newError("checkDestinationFree", ErrConflict, "%s already holds %d objects", uri, len(blobs))
+	}
+	return nil
+}
+
+// uploadFiles streams the files to uri, hashing them on the way.
+func uploadFiles(ctx context.Context, writer BlobWriter, files []localFile, uri string) ([]fileDigest, error) {
+	var digests []fileDigest
+	for _, file := range files {
+		reader, err := os.Open(file.path)
+		if err != nil {
+			return nil, localError("uploadFiles", file.path, err)
+		}
+
+		hash := sha256.New()
+		err = writer.Put(ctx, fileURI(uri, file), io.TeeReader(reader, hash), file.size)
+		reader.Close()
+		if err != nil {
+			log.Debugf("Failed to upload %s: %v", file.path, err)
+			return nil, err
+		}
+		digests = append(digests, fileDigest{Path: file.relative, Sum: hex.EncodeToString(hash.Sum(nil)), Size: file.size})
+	}
+	return digests, nil
+}
+
+// deleteUploaded deletes the files of a failed upload, failures are only
+// logged as the upload already failed.
+func (workspace Workspace) deleteUploaded(writer BlobWriter, files []localFile, uri string) {
+	// The context of the upload may be the reason of the failure
+	ctx, cancel := workspace.artifactStore.cleanupContext()
+	defer cancel()
+
+	for _, file := range files {
+		if err := writer.Delete(ctx, fileURI(uri, file)); err != nil {
+			log.Errorf(err, "Failed to delete %s after a failed upload", fileURI(uri, file))
+		}
+	}
+	log.Debugf("Deleted the files uploaded to %s", uri)
+}
+
+// fileURI returns the destination of a file uploaded to uri.
+func fileURI(uri string, file localFile) string {
+	if file.relative == "" {
+		return uri
+	}
+	return strings.TrimSuffix(uri, "/") + "/" + file.relative
+}
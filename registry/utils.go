@@ -17,64 +17,122 @@ package artifact_registry
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
 	pb "github.com/Vernacular-ai/artifact-registry/protos"
 )
 
-// TODO: Add feature to push custom filter to this
-func prepareFilteredArtifactsList(artifacts []*pb.Artifact, workspaceName string) []*pb.ArtifactData {
+func (artifactStore MLArtifactStore) prepareArtifactsList(ctx context.Context, client pb.MetadataStoreServiceClient, artifacts []*pb.Artifact) ([]*pb.ArtifactData, error) {
+	types := artifactStore.typeRegistry(client)
+	artifactTypeNames := make(map[int64]string)
+
 	var artifactList []*pb.ArtifactData
 	for _, item := range artifacts {
-		if item.CustomProperties["__kf_workspace__"].GetStringValue() != workspaceName {
-			continue
+		if _, ok := artifactTypeNames[item.GetTypeId()]; !ok {
+			artifactType, err := types.ArtifactType(ctx, item.GetTypeId())
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				return nil, err
+			}
+			artifactTypeNames[item.GetTypeId()] = artifactType.GetName()
 		}
+
 		artifactData := &pb.ArtifactData{
-			Id:           item.GetId(),
-			Name:         item.Properties["name"].GetStringValue(),
-			Uri:          item.GetUri(),
-			Version:      item.Properties["version"].GetStringValue(),
-			RunId:        item.CustomProperties["__kf_run__"].GetStringValue(),
-			ArtifactType: pb.ArtifactData_MODEL,
+			Id:             item.GetId(),
+			Name:           artifactName(item),
+			Uri:            item.GetUri(),
+			Version:        artifactVersion(item),
+			RunId:          item.CustomProperties["__kf_run__"].GetStringValue(),
+			ArtifactType:   artifactStore.artifactDataType(artifactTypeNames[item.GetTypeId()]),
+			TypeName:       artifactTypeNames[item.GetTypeId()],
+			Metadata:       artifactMetadata(item),
+			State:          pb.ArtifactData_State(item.GetState()),
+			CreateTime:     timestampFromEpoch(item.CreateTimeSinceEpoch),
+			LastUpdateTime: timestampFromEpoch(item.LastUpdateTimeSinceEpoch),
 		}
 		artifactList = append(artifactList, artifactData)
 	}
-	return artifactList
+	return artifactList, nil
 }
 
-func prepareArtifactsList(artifacts []*pb.Artifact) []*pb.ArtifactData {
-	artifactTypeMap := make(map[int]pb.ArtifactData_ArtifactType)
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5000*time.Millisecond)
-	defer cancel()
-
-	artifactTypes, _ := client.GetArtifactTypes(ctx, &pb.GetArtifactTypesRequest{})
-	for _, artifactType := range artifactTypes.ArtifactTypes {
-		switch artifactType.GetName() {
-		case "kubeflow.org/alpha/data_set":
-			artifactTypeMap[int(artifactType.GetId())] = pb.ArtifactData_DATASET
-		case "kubeflow.org/alpha/metrics":
-			artifactTypeMap[int(artifactType.GetId())] = pb.ArtifactData_METRICS
-		case "kubeflow.org/alpha/model":
-			artifactTypeMap[int(artifactType.GetId())] = pb.ArtifactData_MODEL
-		default:
-			artifactTypeMap[int(artifactType.GetId())] = pb.ArtifactData_OTHER
+// artifactMetadata merges the custom properties and properties of an artifact
+// in a Struct, properties win when both have the same key. Int values are
+// converted to numbers.
+func artifactMetadata(artifact *pb.Artifact) *structpb.Struct {
+	metadata := &structpb.Struct{Fields: map[string]*structpb.Value{}}
+	for key, value := range artifact.GetCustomProperties() {
+		if structValue := metadataValue(value); structValue != nil {
+			metadata.Fields[key] = structValue
 		}
 	}
+	for key, value := range artifact.GetProperties() {
+		if structValue := metadataValue(value); structValue != nil {
+			metadata.Fields[key] = structValue
+		}
+	}
+	return metadata
+}
 
-	var artifactList []*pb.ArtifactData
-	for _, item := range artifacts {
-		artifactData := &pb.ArtifactData{
-			Id:           item.GetId(),
-			Name:         item.Properties["name"].GetStringValue(),
-			Uri:          item.GetUri(),
-			Version:      item.Properties["version"].GetStringValue(),
-			RunId:        item.CustomProperties["__kf_run__"].GetStringValue(),
-			ArtifactType: artifactTypeMap[int(item.GetTypeId())],
+func metadataValue(value *pb.Value) *structpb.Value {
+	switch value.GetValue().(type) {
+	case *pb.Value_IntValue:
+		return structpb.NewNumberValue(float64(value.GetIntValue()))
+	case *pb.Value_DoubleValue:
+		return structpb.NewNumberValue(value.GetDoubleValue())
+	case *pb.Value_StringValue:
+		return structpb.NewStringValue(value.GetStringValue())
+	case *pb.Value_StructValue:
+		return structpb.NewStructValue(value.GetStructValue())
+	}
+	return nil
+}
+
+// timestampFromEpoch converts milliseconds since epoch as stored by MLMD, nil
+// if unset.
+func timestampFromEpoch(milliseconds *int64) *timestamppb.Timestamp {
+	if milliseconds == nil {
+		return nil
+	}
+	return timestamppb.New(time.Unix(0, *milliseconds*int64(time.Millisecond)))
+}
+
+// Number of times a read-modify-write of artifacts is attempted while they
+// are updated concurrently
+const updateAttempts = 3
+
+// errUpdatedConcurrently is returned when MLMD rejects a write because an
+// artifact changed since it was read.
+var errUpdatedConcurrently = fmt.Errorf("artifact was updated concurrently: %w", ErrConflict)
+
+// putArtifactsIfUnchanged writes artifacts in a single call, only if none of
+// them was updated since it was read. It fails with errUpdatedConcurrently
+// otherwise.
+func putArtifactsIfUnchanged(ctx context.Context, client pb.MetadataStoreServiceClient, artifacts []*pb.Artifact) error {
+	_, err := client.PutArtifacts(ctx, &pb.PutArtifactsRequest{
+		Artifacts: artifacts,
+		Options:   &pb.PutArtifactsRequest_Options{AbortIfLatestUpdatedTimeChanged: proto.Bool(true)},
+	})
+	if status.Code(err) == codes.FailedPrecondition {
+		return errUpdatedConcurrently
+	}
+	return err
+}
+
+// containsContext reports whether a context is in a list of contexts.
+func containsContext(contexts []*pb.Context, contextId int64) bool {
+	for _, metadataContext := range contexts {
+		if metadataContext.GetId() == contextId {
+			return true
 		}
-		artifactList = append(artifactList, artifactData)
 	}
-	return artifactList
+	return false
 }
 
 func uniqueList(intSlice []int64) []int64 {
@@ -0,0 +1,128 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Methods to verify artifact payloads and find duplicates
+
+package artifact_registry
+
+import (
+	"context"
+
+	"github.com/Vernacular-ai/vcore/log"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+)
+
+// Verification compares the digest and size recorded for an artifact to the
+// ones of the payload currently at its URI.
+type Verification struct {
+	ArtifactId int64
+	Uri        string
+
+	ExpectedDigest string
+	// Zero if no size was recorded
+	ExpectedSize int64
+	Digest       string
+	Size         int64
+}
+
+// Verified reports whether the payload matches the recorded digest, and the
+// recorded size if any.
+func (verification Verification) Verified() bool {
+	if verification.ExpectedSize > 0 && verification.Size != verification.ExpectedSize {
+		return false
+	}
+	return verification.Digest == verification.ExpectedDigest
+}
+
+// VerifyArtifact re-hashes the payload at the URI of an artifact of this
+// workspace and compares it to the digest recorded at registration. A
+// mismatch is reported by the returned Verification, errors are reserved to
+// artifacts which can not be verified: ErrInvalidArgument if no digest was
+// recorded and ErrNotFound if the payload is missing.
+//
+// Like the other methods of the package, it takes no context: pass one to
+// VerifyArtifactWithContext.
+func (workspace Workspace) VerifyArtifact(artifactId int64) (Verification, error) {
+	return workspace.VerifyArtifactWithContext(context.Background(), artifactId)
+}
+
+// VerifyArtifactWithContext is VerifyArtifact using the provided context for
+// deadlines and cancellation.
+func (workspace Workspace) VerifyArtifactWithContext(ctx context.Context, artifactId int64) (Verification, error) {
+	var verification Verification
+
+	client, err := workspace.artifactStore.metadataClient()
+	if err != nil {
+		return verification, wrapError("VerifyArtifact", err)
+	}
+	artifact, err := workspace.fetchArtifact(ctx, client, artifactId)
+	if err != nil {
+		return verification, wrapError("VerifyArtifact", err)
+	}
+
+	expectedDigest, expectedSize, ok := artifactDigest(artifact)
+	if !ok {
+		return verification, newError("VerifyArtifact", ErrInvalidArgument, "artifact %d has no recorded digest", artifactId)
+	}
+	digest, size, err := workspace.artifactStore.BlobStores().ComputeDigest(ctx, artifact.GetUri())
+	if err != nil {
+		return verification, wrapError("VerifyArtifact", err)
+	}
+
+	verification = Verification{
+		ArtifactId:     artifactId,
+		Uri:            artifact.GetUri(),
+		ExpectedDigest: expectedDigest,
+		ExpectedSize:   expectedSize,
+		Digest:         digest,
+		Size:           size,
+	}
+	if !verification.Verified() {
+		log.Debugf("Artifact %d does not match its digest: expected %s (%d bytes), got %s (%d bytes)",
+			artifactId, expectedDigest, expectedSize, digest, size)
+	}
+	return verification, nil
+}
+
+// FindByDigest returns the artifacts of this workspace recorded with a
+// digest, e.g. to detect a payload registered more than once.
+func (workspace Workspace) FindByDigest(digest string, filters ...Filter) (*pb.ArtifactsResponse, error) {
+	return workspace.FindByDigestWithContext(context.Background(), digest, filters...)
+}
+
+// FindByDigestWithContext is FindByDigest using the provided context for
+// deadlines and cancellation.
+func (workspace Workspace) FindByDigestWithContext(ctx context.Context, digest string, filters ...Filter) (*pb.ArtifactsResponse, error) {
+	var artifactsResponse *pb.ArtifactsResponse
+
+	digest, err := normalizeDigest(digest)
+	if err != nil {
+		return artifactsResponse, wrapError("FindByDigest", err)
+	}
+	artifactsResponse, _, err = workspace.listArtifacts(ctx, nil, ListOptions{}, append([]Filter{HasDigest(digest)}, filters...))
+	if err != nil {
+		return artifactsResponse, wrapError("FindByDigest", err)
+	}
+	return artifactsResponse, nil
+}
+
+// HasDigest matches artifacts recorded with a digest, with or without the
+// sha256: prefix.
+func HasDigest(digest string) Filter {
+	normalized, err := normalizeDigest(digest)
+	return func(artifact *pb.Artifact) bool {
+		recorded, _, ok := artifactDigest(artifact)
+		return err == nil && ok && recorded == normalized
+	}
+}
@@ -0,0 +1,287 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Methods to look up the versions of a model
+
+package artifact_registry
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+)
+
+// VersionOrder selects how the latest version of a model is picked
+type VersionOrder int
+
+const (
+	// The most recently created version, the default
+	ByCreateTime VersionOrder = iota
+	// The highest semantic version, e.g. 1.10.0 after 1.9.2. Versions which
+	// are not semantic versions, such as Kubeflow's UUID-style versions, are
+	// ordered by create time before all semantic versions.
+	ByVersion
+)
+
+// uuidVersion matches UUID-style versions, e.g.
+// model_version_69389a49-3c4b-4e0f-9d2a-4b8f3f1f5c6e, with an optional prefix
+var uuidVersion = regexp.MustCompile(`^(?:[A-Za-z_]*_)?([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})$`)
+
+// ListModelVersions returns the versions of the named model in this workspace,
+// oldest first.
+func (workspace Workspace) ListModelVersions(name string) ([]*pb.ArtifactData, error) {
+	return workspace.ListModelVersionsWithContext(context.Background(), name)
+}
+
+// ListModelVersionsWithContext is ListModelVersions using the provided context
+// for deadlines and cancellation.
+func (workspace Workspace) ListModelVersionsWithContext(ctx context.Context, name string) ([]*pb.ArtifactData, error) {
+	versions, err := workspace.modelVersions(ctx, name)
+	if err != nil {
+		return versions, wrapError("ListModelVersions", err)
+	}
+	return versions, nil
+}
+
+// GetLatestModel returns the latest version of the named model in this
+// workspace.
+func (workspace Workspace) GetLatestModel(name string, order VersionOrder) (*pb.ArtifactData, error) {
+	return workspace.GetLatestModelWithContext(context.Background(), name, order)
+}
+
+// GetLatestModelWithContext is GetLatestModel using the provided context for
+// deadlines and cancellation.
+func (workspace Workspace) GetLatestModelWithContext(ctx context.Context, name string, order VersionOrder) (*pb.ArtifactData, error) {
+	var artifactData *pb.ArtifactData
+
+	versions, err := workspace.modelVersions(ctx, name)
+	if err != nil {
+		return artifactData, wrapError("GetLatestModel", err)
+	}
+	if len(versions) == 0 {
+		return artifactData, newError("GetLatestModel", ErrNotFound, "model %s does not exist", name)
+	}
+
+	switch order {
+	case ByCreateTime:
+	case ByVersion:
+		sort.SliceStable(versions, func(i, j int) bool {
+			return compareVersions(versions[i].GetVersion(), versions[j].GetVersion()) < 0
+		})
+	default:
+		return artifactData, newError("GetLatestModel", ErrInvalidArgument, "unknown version order %d", order)
+	}
+
+	return versions[len(versions)-1], nil
+}
+
+// GetModelVersion returns a version of the named model in this workspace.
+// Semantic versions match regardless of a leading "v" and of build metadata,
+// UUID-style versions match by their UUID alone, e.g. "69389a49-..." matches
+// "model_version_69389a49-...".
+func (workspace Workspace) GetModelVersion(name string, version string) (*pb.ArtifactData, error) {
+	return workspace.GetModelVersionWithContext(context.Background(), name, version)
+}
+
+// GetModelVersionWithContext is GetModelVersion using the provided context for
+// deadlines and cancellation.
+func (workspace Workspace) GetModelVersionWithContext(ctx context.Context, name string, version string) (*pb.ArtifactData, error) {
+	var artifactData *pb.ArtifactData
+
+	if version == "" {
+		return artifactData, newError("GetModelVersion", ErrInvalidArgument, "model version is required")
+	}
+
+	versions, err := workspace.modelVersions(ctx, name)
+	if err != nil {
+		return artifactData, wrapError("GetModelVersion", err)
+	}
+
+	// Exact matches win over equivalent versions
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i].GetVersion() == version {
+			return versions[i], nil
+		}
+	}
+	for i := len(versions) - 1; i >= 0; i-- {
+		if sameVersion(versions[i].GetVersion(), version) {
+			return versions[i], nil
+		}
+	}
+
+	return artifactData, newError("GetModelVersion", ErrNotFound, "model %s has no version %s", name, version)
+}
+
+// modelVersions fetches the models of the workspace with the given name and
+// matching the filters, ordered by create time then ID.
+func (workspace Workspace) modelVersions(ctx context.Context, name string, filters ...Filter) ([]*pb.ArtifactData, error) {
+	if name == "" {
+		return nil, newError("modelVersions", ErrInvalidArgument, "model name is required")
+	}
+
+	modelRequest := &pb.ArtifactByTypeRequest{ArtifactType: pb.ArtifactByTypeRequest_MODEL}
+	nameFilter := func(artifact *pb.Artifact) bool {
+		return artifactName(artifact) == name
+	}
+	artifactsResponse, _, err := workspace.listArtifactsByType(ctx, modelRequest, ListOptions{}, append([]Filter{nameFilter}, filters...))
+	if err != nil {
+		return nil, err
+	}
+
+	versions := artifactsResponse.GetArtifacts()
+	sort.SliceStable(versions, func(i, j int) bool {
+		iTime, jTime := versions[i].GetCreateTime().AsTime(), versions[j].GetCreateTime().AsTime()
+		if !iTime.Equal(jTime) {
+			return iTime.Before(jTime)
+		}
+		return versions[i].GetId() < versions[j].GetId()
+	})
+	return versions, nil
+}
+
+// semanticVersion is a parsed semantic version, see https://semver.org
+type semanticVersion struct {
+	major, minor, patch int64
+	preRelease          []string
+}
+
+// parseSemanticVersion parses versions such as 1.2.3, v1.2.3-rc.1 or
+// 1.2.3+build.5, build metadata is ignored.
+func parseSemanticVersion(version string) (semanticVersion, bool) {
+	var parsed semanticVersion
+
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexByte(version, '+'); i >= 0 {
+		version = version[:i]
+	}
+	if i := strings.IndexByte(version, '-'); i >= 0 {
+		parsed.preRelease = strings.Split(version[i+1:], ".")
+		version = version[:i]
+		for _, identifier := range parsed.preRelease {
+			if identifier == "" {
+				return parsed, false
+			}
+		}
+	}
+
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return parsed, false
+	}
+	numbers := make([]int64, 3)
+	for i, part := range parts {
+		number, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || number < 0 {
+			return parsed, false
+		}
+		numbers[i] = number
+	}
+	parsed.major, parsed.minor, parsed.patch = numbers[0], numbers[1], numbers[2]
+
+	return parsed, true
+}
+
+// compare returns -1, 0 or 1 following the semantic versioning precedence.
+func (version semanticVersion) compare(other semanticVersion) int {
+	for _, pair := range [][2]int64{{version.major, other.major}, {version.minor, other.minor}, {version.patch, other.patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	// A pre-release precedes the release
+	switch {
+	case len(version.preRelease) == 0 && len(other.preRelease) == 0:
+		return 0
+	case len(version.preRelease) == 0:
+		return 1
+	case len(other.preRelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(version.preRelease) && i < len(other.preRelease); i++ {
+		if comparison := comparePreRelease(version.preRelease[i], other.preRelease[i]); comparison != 0 {
+			return comparison
+		}
+	}
+	switch {
+	case len(version.preRelease) < len(other.preRelease):
+		return -1
+	case len(version.preRelease) > len(other.preRelease):
+		return 1
+	}
+	return 0
+}
+
+// comparePreRelease compares pre-release identifiers, numeric identifiers
+// precede alphanumeric ones.
+func comparePreRelease(identifier string, other string) int {
+	number, err := strconv.ParseUint(identifier, 10, 64)
+	isNumber := err == nil
+	otherNumber, err := strconv.ParseUint(other, 10, 64)
+	otherIsNumber := err == nil
+
+	switch {
+	case isNumber && otherIsNumber:
+		if number < otherNumber {
+			return -1
+		}
+		if number > otherNumber {
+			return 1
+		}
+		return 0
+	case isNumber:
+		return -1
+	case otherIsNumber:
+		return 1
+	}
+	return strings.Compare(identifier, other)
+}
+
+// compareVersions orders versions for ByVersion: semantic versions follow
+// their precedence and come after every other version, which compare equal.
+func compareVersions(version string, other string) int {
+	parsed, isSemantic := parseSemanticVersion(version)
+	otherParsed, otherIsSemantic := parseSemanticVersion(other)
+
+	switch {
+	case isSemantic && otherIsSemantic:
+		return parsed.compare(otherParsed)
+	case isSemantic:
+		return 1
+	case otherIsSemantic:
+		return -1
+	}
+	return 0
+}
+
+// sameVersion reports whether two versions are equivalent semantic versions
+// or UUID-style versions with the same UUID.
+func sameVersion(version string, other string) bool {
+	parsed, isSemantic := parseSemanticVersion(version)
+	otherParsed, otherIsSemantic := parseSemanticVersion(other)
+	if isSemantic && otherIsSemantic {
+		return parsed.compare(otherParsed) == 0
+	}
+
+	uuid := uuidVersion.FindStringSubmatch(version)
+	otherUuid := uuidVersion.FindStringSubmatch(other)
+	return uuid != nil && otherUuid != nil && strings.EqualFold(uuid[1], otherUuid[1])
+}
@@ -0,0 +1,200 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Methods to create and list workspaces
+
+package artifact_registry
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Vernacular-ai/vcore/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+)
+
+// WorkspaceSpec describes a workspace to create
+type WorkspaceSpec struct {
+	Name        string
+	Description string
+	Owner       string
+}
+
+// CreateWorkspace returns the named workspace, creating it if it does not
+// exist yet. The metadata of an existing workspace is left untouched.
+func (artifactStore MLArtifactStore) CreateWorkspace(spec WorkspaceSpec) (Workspace, error) {
+	return artifactStore.CreateWorkspaceWithContext(context.Background(), spec)
+}
+
+// CreateWorkspaceWithContext is CreateWorkspace using the provided context for
+// deadlines and cancellation.
+func (artifactStore MLArtifactStore) CreateWorkspaceWithContext(ctx context.Context, spec WorkspaceSpec) (Workspace, error) {
+	var workspaceResponse Workspace
+
+	if spec.Name == "" {
+		return workspaceResponse, newError("CreateWorkspace", ErrInvalidArgument, "workspace name is required")
+	}
+
+	client, err := artifactStore.metadataClient()
+	if err != nil {
+		return workspaceResponse, wrapError("CreateWorkspace", err)
+	}
+
+	contextType, err := artifactStore.getOrCreateContextType(ctx, client, CONTEXT_TYPE_NAME)
+	if err != nil {
+		return workspaceResponse, wrapError("CreateWorkspace", err)
+	}
+
+	workspaceResponse, err = artifactStore.GetWorkspaceWithContext(ctx, &pb.Workspace{Name: spec.Name})
+	if err == nil {
+		return workspaceResponse, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return workspaceResponse, wrapError("CreateWorkspace", err)
+	}
+
+	workspaceContext := &pb.Context{
+		TypeId:           contextType.Id,
+		Name:             &spec.Name,
+		Properties:       map[string]*pb.Value{},
+		CustomProperties: map[string]*pb.Value{},
+	}
+	schema := contextType.GetProperties()
+	setProperty(workspaceContext.Properties, workspaceContext.CustomProperties, schema, "name", stringValue(spec.Name))
+	if spec.Description != "" {
+		setProperty(workspaceContext.Properties, workspaceContext.CustomProperties, schema, "description", stringValue(spec.Description))
+	}
+	if spec.Owner != "" {
+		setProperty(workspaceContext.Properties, workspaceContext.CustomProperties, schema, "owner", stringValue(spec.Owner))
+	}
+	createTime := time.Now().UTC().Format(time.RFC3339)
+	setProperty(workspaceContext.Properties, workspaceContext.CustomProperties, schema, "create_time", stringValue(createTime))
+
+	_, err = client.PutContexts(ctx, &pb.PutContextsRequest{Contexts: []*pb.Context{workspaceContext}})
+	if status.Code(err) == codes.AlreadyExists {
+		// Created concurrently by someone else
+		return artifactStore.GetWorkspaceWithContext(ctx, &pb.Workspace{Name: spec.Name})
+	}
+	if err != nil {
+		log.Debugf("Failed to create workspace %s: %v", spec.Name, err)
+		return workspaceResponse, wrapError("CreateWorkspace", err)
+	}
+	log.Debugf("Created workspace %s", spec.Name)
+
+	return artifactStore.GetWorkspaceWithContext(ctx, &pb.Workspace{Name: spec.Name})
+}
+
+// ListWorkspaces returns a page of the workspaces in MLMD along with the token
+// of the next page, which is empty on the last page.
+func (artifactStore MLArtifactStore) ListWorkspaces(options ListOptions) ([]Workspace, string, error) {
+	return artifactStore.ListWorkspacesWithContext(context.Background(), options)
+}
+
+// ListWorkspacesWithContext is ListWorkspaces using the provided context for
+// deadlines and cancellation.
+func (artifactStore MLArtifactStore) ListWorkspacesWithContext(ctx context.Context, options ListOptions) ([]Workspace, string, error) {
+	var workspaces []Workspace
+
+	client, err := artifactStore.metadataClient()
+	if err != nil {
+		return workspaces, "", wrapError("ListWorkspaces", err)
+	}
+
+	contextsRequest := &pb.GetContextsByTypeRequest{
+		TypeName: &CONTEXT_TYPE_NAME,
+		Options:  options.listOperationOptions(),
+	}
+	response, err := client.GetContextsByType(ctx, contextsRequest)
+	if err != nil {
+		log.Debugf("Failed to list workspaces: %v", err)
+		return workspaces, "", wrapError("ListWorkspaces", err)
+	}
+
+	for _, workspaceContext := range response.GetContexts() {
+		workspaces = append(workspaces, artifactStore.workspaceFromContext(workspaceContext))
+	}
+
+	return workspaces, response.GetNextPageToken(), nil
+}
+
+// workspaceFromContext reads a workspace and its metadata from its MLMD
+// context.
+func (artifactStore MLArtifactStore) workspaceFromContext(workspaceContext *pb.Context) Workspace {
+	workspace := Workspace{
+		Id:            workspaceContext.GetId(),
+		Name:          workspaceContext.GetName(),
+		Description:   contextProperty(workspaceContext, "description"),
+		Owner:         contextProperty(workspaceContext, "owner"),
+		artifactStore: artifactStore,
+	}
+
+	// Kubeflow stores the creation time as a string, fall back to the time
+	// MLMD created the context
+	if createTime, err := time.Parse(time.RFC3339, contextProperty(workspaceContext, "create_time")); err == nil {
+		workspace.CreateTime = createTime
+	} else if workspaceContext.CreateTimeSinceEpoch != nil {
+		workspace.CreateTime = time.Unix(0, workspaceContext.GetCreateTimeSinceEpoch()*int64(time.Millisecond)).UTC()
+	}
+
+	return workspace
+}
+
+// getOrCreateContextType fetches the named context type, registering it with
+// the Kubeflow workspace properties if it does not exist.
+func (artifactStore MLArtifactStore) getOrCreateContextType(ctx context.Context, client pb.MetadataStoreServiceClient, typeName string) (*pb.ContextType, error) {
+	types := artifactStore.typeRegistry(client)
+	contextType, err := types.ContextTypeByName(ctx, typeName)
+	if err == nil {
+		return contextType, nil
+	}
+	if !errors.Is(err, ErrNotFound) {
+		log.Debugf("Failed to fetch context type %s: %v", typeName, err)
+		return nil, err
+	}
+
+	canAddFields := true
+	contextType = &pb.ContextType{
+		Name: &typeName,
+		Properties: map[string]pb.PropertyType{
+			"name":        pb.PropertyType_STRING,
+			"description": pb.PropertyType_STRING,
+			"owner":       pb.PropertyType_STRING,
+			"create_time": pb.PropertyType_STRING,
+		},
+	}
+	typeResponse, err := client.PutContextType(ctx, &pb.PutContextTypeRequest{
+		ContextType:  contextType,
+		CanAddFields: &canAddFields,
+	})
+	if err != nil {
+		log.Debugf("Failed to create context type %s: %v", typeName, err)
+		return nil, err
+	}
+	contextType.Id = typeResponse.TypeId
+	types.Invalidate()
+
+	return contextType, nil
+}
+
+// contextProperty returns a string property of a context whether it is
+// defined by its type or custom.
+func contextProperty(metadataContext *pb.Context, key string) string {
+	if value, ok := metadataContext.GetProperties()[key]; ok {
+		return value.GetStringValue()
+	}
+	return metadataContext.GetCustomProperties()[key].GetStringValue()
+}
@@ -0,0 +1,111 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Mapping of registry errors to HTTP responses
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Vernacular-ai/vcore/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	registry "github.com/Vernacular-ai/artifact-registry/registry"
+)
+
+// Code returns the gRPC code of an error, classified by its registry error
+// kind first and by the code of the MLMD error otherwise.
+func Code(err error) codes.Code {
+	switch {
+	case err == nil:
+		return codes.OK
+	case errors.Is(err, registry.ErrNotFound):
+		return codes.NotFound
+	case errors.Is(err, registry.ErrInvalidArgument):
+		return codes.InvalidArgument
+	case errors.Is(err, registry.ErrConflict):
+		return codes.FailedPrecondition
+	case errors.Is(err, registry.ErrUnavailable):
+		return codes.Unavailable
+	case errors.Is(err, registry.ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	}
+	return status.Code(err)
+}
+
+// HTTPStatus returns the HTTP status of an error, following the mapping of
+// gRPC codes used by Google APIs except for ErrConflict which is a 409.
+func HTTPStatus(err error) int {
+	if errors.Is(err, registry.ErrConflict) {
+		return http.StatusConflict
+	}
+	return httpStatusFromCode(Code(err))
+}
+
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		// Client Closed Request, as nginx
+		return 499
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// writeError writes err as a google.rpc.Status message.
+func writeError(w http.ResponseWriter, err error) {
+	httpStatus := HTTPStatus(err)
+	if httpStatus >= http.StatusInternalServerError {
+		log.Errorf(err, "Failed to serve request")
+	} else {
+		log.Debugf("Rejected request: %v", err)
+	}
+	writeStatus(w, httpStatus, status.New(Code(err), errorMessage(err)))
+}
+
+func writeStatus(w http.ResponseWriter, httpStatus int, st *status.Status) {
+	writeMessage(w, httpStatus, st.Proto())
+}
+
+// errorMessage strips the gRPC prefix of errors created by package status.
+func errorMessage(err error) string {
+	if st, ok := status.FromError(err); ok {
+		return st.Message()
+	}
+	return err.Error()
+}
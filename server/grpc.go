@@ -0,0 +1,136 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// gRPC implementation of the ArtifactRegistryService
+
+package server
+
+import (
+	"context"
+
+	"github.com/Vernacular-ai/vcore/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+	registry "github.com/Vernacular-ai/artifact-registry/registry"
+)
+
+// GRPCServer implements the ArtifactRegistryService of
+// protos/artifact-registry.proto with an MLArtifactStore.
+//
+//	grpcServer := grpc.NewServer()
+//	pb.RegisterArtifactRegistryServiceServer(grpcServer, server.NewGRPCServer(artifactStore))
+//	grpcServer.Serve(listener)
+type GRPCServer struct {
+	pb.UnimplementedArtifactRegistryServiceServer
+
+	artifactStore registry.MLArtifactStore
+}
+
+// NewGRPCServer returns a service reading from artifactStore, which is not
+// closed by the service.
+func NewGRPCServer(artifactStore registry.MLArtifactStore) *GRPCServer {
+	return &GRPCServer{artifactStore: artifactStore}
+}
+
+// GetArtifactsByID returns artifacts by ID, in any workspace.
+func (server *GRPCServer) GetArtifactsByID(ctx context.Context, request *pb.MLArtifact) (*pb.ArtifactsResponse, error) {
+	if len(request.GetIds()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "ids are required")
+	}
+	artifactsResponse, err := server.artifactStore.GetArtifactsByIDWithContext(ctx, request)
+	return artifactsResponse, grpcError(err)
+}
+
+// GetWorkspace returns the workspace of the requested name.
+func (server *GRPCServer) GetWorkspace(ctx context.Context, request *pb.Workspace) (*pb.Workspace, error) {
+	workspace, err := server.workspace(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Workspace{Name: workspace.Name}, nil
+}
+
+// GetArtifactsByWorkspace returns the artifacts of a workspace.
+func (server *GRPCServer) GetArtifactsByWorkspace(ctx context.Context, request *pb.Workspace) (*pb.ArtifactsResponse, error) {
+	workspace, err := server.workspace(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	artifactsResponse, err := workspace.GetArtifactsByWorkspaceWithContext(ctx)
+	return artifactsResponse, grpcError(err)
+}
+
+// GetArtifactsByTypeWorkspace returns the artifacts of a workspace having a
+// certain type.
+func (server *GRPCServer) GetArtifactsByTypeWorkspace(ctx context.Context, request *pb.GetArtifactsByTypeWorkspaceRequest) (*pb.ArtifactsResponse, error) {
+	if request.GetArtifactTypeRequest() == nil {
+		return nil, status.Error(codes.InvalidArgument, "artifact_type_request is required")
+	}
+	workspace, err := server.workspace(ctx, request.GetWorkspace())
+	if err != nil {
+		return nil, err
+	}
+	artifactsResponse, err := workspace.GetArtifactsByTypeWorkspaceWithContext(ctx, request.GetArtifactTypeRequest())
+	return artifactsResponse, grpcError(err)
+}
+
+// GetLineageByRun returns the artifacts of a workspace associated with a
+// Kubeflow run.
+func (server *GRPCServer) GetLineageByRun(ctx context.Context, request *pb.GetLineageByRunRequest) (*pb.ArtifactsResponse, error) {
+	if request.GetArtifactsByRunRequest().GetRunId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "artifacts_by_run_request.run_id is required")
+	}
+	workspace, err := server.workspace(ctx, request.GetWorkspace())
+	if err != nil {
+		return nil, err
+	}
+	artifactsResponse, err := workspace.GetLineageByRunWithContext(ctx, request.GetArtifactsByRunRequest())
+	return artifactsResponse, grpcError(err)
+}
+
+// GetLineageByModel returns the artifacts of a workspace related to a model
+// through its executions.
+func (server *GRPCServer) GetLineageByModel(ctx context.Context, request *pb.GetLineageByModelRequest) (*pb.ArtifactsResponse, error) {
+	if request.GetArtifactsByModelRequest().GetModelId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "artifacts_by_model_request.model_id must be positive")
+	}
+	workspace, err := server.workspace(ctx, request.GetWorkspace())
+	if err != nil {
+		return nil, err
+	}
+	artifactsResponse, err := workspace.GetLineageByModelWithContext(ctx, request.GetArtifactsByModelRequest())
+	return artifactsResponse, grpcError(err)
+}
+
+func (server *GRPCServer) workspace(ctx context.Context, request *pb.Workspace) (registry.Workspace, error) {
+	if request.GetName() == "" {
+		return registry.Workspace{}, status.Error(codes.InvalidArgument, "workspace name is required")
+	}
+	workspace, err := server.artifactStore.GetWorkspaceWithContext(ctx, request)
+	return workspace, grpcError(err)
+}
+
+// grpcError converts a registry error to a status error with the code of its
+// kind, see Code.
+func grpcError(err error) error {
+	if err == nil {
+		return nil
+	}
+	code := Code(err)
+	if httpStatusFromCode(code) >= 500 {
+		log.Errorf(err, "Failed to serve request")
+	}
+	return status.Error(code, errorMessage(err))
+}
@@ -0,0 +1,145 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+	registry "github.com/Vernacular-ai/artifact-registry/registry"
+	"github.com/Vernacular-ai/artifact-registry/server"
+)
+
+// newGRPCClient serves the ArtifactRegistryService in memory and returns a
+// client along with the function stopping both.
+func newGRPCClient(t *testing.T) (pb.ArtifactRegistryServiceClient, func()) {
+	artifactStore, err := registry.NewArtifactStore(registry.WithClient(exampleMLMD()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	pb.RegisterArtifactRegistryServiceServer(grpcServer, server.NewGRPCServer(artifactStore))
+	go grpcServer.Serve(listener)
+
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) {
+		return listener.Dial()
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pb.NewArtifactRegistryServiceClient(conn), func() {
+		conn.Close()
+		grpcServer.Stop()
+	}
+}
+
+func artifactNames(artifactsResponse *pb.ArtifactsResponse) string {
+	var names []string
+	for _, artifact := range artifactsResponse.GetArtifacts() {
+		names = append(names, artifact.GetName())
+	}
+	return fmt.Sprint(names)
+}
+
+func TestGRPCServer(t *testing.T) {
+	client, stop := newGRPCClient(t)
+	defer stop()
+	ctx := context.Background()
+	workspace := &pb.Workspace{Name: "team/vision"}
+
+	got, err := client.GetWorkspace(ctx, workspace)
+	if err != nil || got.GetName() != "team/vision" {
+		t.Errorf("GetWorkspace = %v, %v", got, err)
+	}
+
+	artifacts, err := client.GetArtifactsByWorkspace(ctx, workspace)
+	if err != nil || artifactNames(artifacts) != "[mnist MNIST MNIST-evaluation]" {
+		t.Errorf("GetArtifactsByWorkspace = %s, %v", artifactNames(artifacts), err)
+	}
+
+	artifacts, err = client.GetArtifactsByTypeWorkspace(ctx, &pb.GetArtifactsByTypeWorkspaceRequest{
+		Workspace:           workspace,
+		ArtifactTypeRequest: &pb.ArtifactByTypeRequest{ArtifactType: pb.ArtifactByTypeRequest_METRICS},
+	})
+	if err != nil || artifactNames(artifacts) != "[MNIST-evaluation]" {
+		t.Errorf("GetArtifactsByTypeWorkspace = %s, %v", artifactNames(artifacts), err)
+	}
+
+	artifacts, err = client.GetLineageByRun(ctx, &pb.GetLineageByRunRequest{
+		Workspace:             workspace,
+		ArtifactsByRunRequest: &pb.ArtifactsByRunRequest{RunId: "run-2021-03-30T16:50:45.608098"},
+	})
+	if err != nil || artifactNames(artifacts) != "[mnist MNIST]" {
+		t.Errorf("GetLineageByRun = %s, %v", artifactNames(artifacts), err)
+	}
+
+	artifacts, err = client.GetLineageByModel(ctx, &pb.GetLineageByModelRequest{
+		Workspace:               workspace,
+		ArtifactsByModelRequest: &pb.ArtifactsByModelRequest{ModelId: 2},
+	})
+	if err != nil || artifactNames(artifacts) != "[mnist MNIST]" {
+		t.Errorf("GetLineageByModel = %s, %v", artifactNames(artifacts), err)
+	}
+
+	artifacts, err = client.GetArtifactsByID(ctx, &pb.MLArtifact{Ids: []int64{2}})
+	if err != nil || artifactNames(artifacts) != "[MNIST]" {
+		t.Errorf("GetArtifactsByID = %s, %v", artifactNames(artifacts), err)
+	}
+}
+
+func TestGRPCServerErrors(t *testing.T) {
+	client, stop := newGRPCClient(t)
+	defer stop()
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{"missing workspace", func() error {
+			_, err := client.GetArtifactsByWorkspace(ctx, &pb.Workspace{Name: "missing"})
+			return err
+		}, codes.NotFound},
+		{"no workspace name", func() error {
+			_, err := client.GetWorkspace(ctx, &pb.Workspace{})
+			return err
+		}, codes.InvalidArgument},
+		{"no artifact type", func() error {
+			_, err := client.GetArtifactsByTypeWorkspace(ctx, &pb.GetArtifactsByTypeWorkspaceRequest{Workspace: &pb.Workspace{Name: "team/vision"}})
+			return err
+		}, codes.InvalidArgument},
+		{"unknown artifact type", func() error {
+			_, err := client.GetArtifactsByTypeWorkspace(ctx, &pb.GetArtifactsByTypeWorkspaceRequest{
+				Workspace:           &pb.Workspace{Name: "team/vision"},
+				ArtifactTypeRequest: &pb.ArtifactByTypeRequest{ArtifactType: 42},
+			})
+			return err
+		}, codes.InvalidArgument},
+		{"no run ID", func() error {
+			_, err := client.GetLineageByRun(ctx, &pb.GetLineageByRunRequest{Workspace: &pb.Workspace{Name: "team/vision"}})
+			return err
+		}, codes.InvalidArgument},
+		{"no model ID", func() error {
+			_, err := client.GetLineageByModel(ctx, &pb.GetLineageByModelRequest{Workspace: &pb.Workspace{Name: "team/vision"}})
+			return err
+		}, codes.InvalidArgument},
+		{"no artifact IDs", func() error {
+			_, err := client.GetArtifactsByID(ctx, &pb.MLArtifact{})
+			return err
+		}, codes.InvalidArgument},
+	}
+	for _, test := range tests {
+		if code := status.Code(test.call()); code != test.code {
+			t.Errorf("%s: code = %s, want %s", test.name, code, test.code)
+		}
+	}
+}
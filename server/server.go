@@ -0,0 +1,204 @@
+/* Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+==============================================================================*/
+
+// Package server serves the artifact views of the registry over HTTP, encoding
+// the messages of protos/artifact-registry.proto as JSON for clients which
+// can not use the Go SDK.
+//
+//	artifactStore, err := registry.NewArtifactStore(registry.WithAddress("localhost", "8080"))
+//	...
+//	http.ListenAndServe(":8000", server.NewServer(artifactStore))
+//
+// Routes, all of them GET:
+//
+//	/v1/workspaces/{workspace}                             Workspace
+//	/v1/workspaces/{workspace}/artifacts                   ArtifactsResponse
+//	/v1/workspaces/{workspace}/artifacts?artifact_type=MODEL
+//	/v1/workspaces/{workspace}/artifacts?type_name=system.Model
+//	/v1/workspaces/{workspace}/runs/{run_id}/artifacts     ArtifactsResponse
+//	/v1/workspaces/{workspace}/models/{model_id}/lineage   ArtifactsResponse
+//
+// Errors are google.rpc.Status messages with the HTTP status mapped from the
+// kind of the registry error, see HTTPStatus.
+//
+// The same views are served over gRPC as the ArtifactRegistryService, see
+// GRPCServer.
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Vernacular-ai/vcore/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+	registry "github.com/Vernacular-ai/artifact-registry/registry"
+)
+
+const workspacesPrefix = "/v1/workspaces/"
+
+// Server is an http.Handler serving the registry API.
+type Server struct {
+	artifactStore registry.MLArtifactStore
+}
+
+// NewServer returns a server reading from artifactStore, which is not closed
+// by the server.
+func NewServer(artifactStore registry.MLArtifactStore) *Server {
+	return &Server{artifactStore: artifactStore}
+}
+
+// route is a request matched to an API method along with its validated path
+// parameters.
+type route struct {
+	handler   func(ctx context.Context, workspace registry.Workspace, route route, query url.Values) (proto.Message, error)
+	query     []string
+	workspace string
+	runId     string
+	modelId   int64
+}
+
+// ServeHTTP serves a request of the registry API.
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	route, err := matchRoute(r.URL)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeStatus(w, http.StatusMethodNotAllowed, status.Newf(codes.Unimplemented, "method %s is not allowed", r.Method))
+		return
+	}
+	query := r.URL.Query()
+	for key := range query {
+		if !containsString(route.query, key) {
+			writeError(w, status.Errorf(codes.InvalidArgument, "unknown query parameter %q", key))
+			return
+		}
+	}
+
+	workspace, err := server.artifactStore.GetWorkspaceWithContext(r.Context(), &pb.Workspace{Name: route.workspace})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	message, err := route.handler(r.Context(), workspace, route, query)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeMessage(w, http.StatusOK, message)
+}
+
+// matchRoute parses the path of a request, whose segments may be escaped,
+// e.g. a workspace named "team/a" is requested as /v1/workspaces/team%2Fa.
+func matchRoute(requestURL *url.URL) (route, error) {
+	var matched route
+
+	path := requestURL.EscapedPath()
+	if !strings.HasPrefix(path, workspacesPrefix) {
+		return matched, status.Errorf(codes.NotFound, "no route for %s", requestURL.Path)
+	}
+	segments := strings.Split(strings.TrimSuffix(strings.TrimPrefix(path, workspacesPrefix), "/"), "/")
+	for i, segment := range segments {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil || unescaped == "" {
+			return matched, status.Errorf(codes.InvalidArgument, "invalid path %s", requestURL.Path)
+		}
+		segments[i] = unescaped
+	}
+	matched.workspace = segments[0]
+
+	switch {
+	case len(segments) == 1:
+		matched.handler = getWorkspace
+	case len(segments) == 2 && segments[1] == "artifacts":
+		matched.handler = getArtifacts
+		matched.query = []string{"artifact_type", "type_name"}
+	case len(segments) == 4 && segments[1] == "runs" && segments[3] == "artifacts":
+		matched.handler = getLineageByRun
+		matched.runId = segments[2]
+	case len(segments) == 4 && segments[1] == "models" && segments[3] == "lineage":
+		modelId, err := strconv.ParseInt(segments[2], 10, 64)
+		if err != nil || modelId <= 0 {
+			return matched, status.Errorf(codes.InvalidArgument, "invalid model_id %q", segments[2])
+		}
+		matched.handler = getLineageByModel
+		matched.modelId = modelId
+	default:
+		return matched, status.Errorf(codes.NotFound, "no route for %s", requestURL.Path)
+	}
+	return matched, nil
+}
+
+func getWorkspace(ctx context.Context, workspace registry.Workspace, route route, query url.Values) (proto.Message, error) {
+	return &pb.Workspace{Name: workspace.Name}, nil
+}
+
+// getArtifacts serves GetArtifactsByWorkspace, or GetArtifactsByTypeWorkspace
+// when a type is requested.
+func getArtifacts(ctx context.Context, workspace registry.Workspace, route route, query url.Values) (proto.Message, error) {
+	if query.Get("artifact_type") == "" && query.Get("type_name") == "" {
+		return workspace.GetArtifactsByWorkspaceWithContext(ctx)
+	}
+
+	artifactTypeRequest := &pb.ArtifactByTypeRequest{TypeName: query.Get("type_name")}
+	if name := query.Get("artifact_type"); name != "" {
+		value, ok := pb.ArtifactByTypeRequest_ArtifactType_value[strings.ToUpper(name)]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown artifact_type %q, expected MODEL, METRICS or DATASET", name)
+		}
+		artifactTypeRequest.ArtifactType = pb.ArtifactByTypeRequest_ArtifactType(value)
+	}
+	return workspace.GetArtifactsByTypeWorkspaceWithContext(ctx, artifactTypeRequest)
+}
+
+func getLineageByRun(ctx context.Context, workspace registry.Workspace, route route, query url.Values) (proto.Message, error) {
+	return workspace.GetLineageByRunWithContext(ctx, &pb.ArtifactsByRunRequest{RunId: route.runId})
+}
+
+func getLineageByModel(ctx context.Context, workspace registry.Workspace, route route, query url.Values) (proto.Message, error) {
+	return workspace.GetLineageByModelWithContext(ctx, &pb.ArtifactsByModelRequest{ModelId: route.modelId})
+}
+
+// writeMessage writes a message with the JSON mapping of protobuf, including
+// fields set to their default value so that clients need not know them.
+func writeMessage(w http.ResponseWriter, statusCode int, message proto.Message) {
+	encoded, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(message)
+	if err != nil {
+		log.Errorf(err, "Failed to encode response")
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(statusCode)
+	w.Write(encoded)
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
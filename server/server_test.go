@@ -0,0 +1,203 @@
+package server_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	pb "github.com/Vernacular-ai/artifact-registry/protos"
+	registry "github.com/Vernacular-ai/artifact-registry/registry"
+	"github.com/Vernacular-ai/artifact-registry/registry/mlmdtest"
+	"github.com/Vernacular-ai/artifact-registry/server"
+)
+
+func exampleMLMD() *mlmdtest.Store {
+	mlmd := mlmdtest.NewStore()
+	mlmd.SeedKubeflowTypes()
+
+	run := "run-2021-03-30T16:50:45.608098"
+	dataset := mlmd.SeedArtifact(mlmdtest.Artifact{
+		Type:      mlmdtest.DatasetType,
+		Name:      "mnist",
+		URI:       "gcs://my-bucket/mnist-data",
+		Workspace: "team/vision",
+		RunID:     run,
+	})
+	model := mlmd.SeedArtifact(mlmdtest.Artifact{
+		Type:      mlmdtest.ModelType,
+		Name:      "MNIST",
+		Version:   "1.0.0",
+		URI:       "gcs://my-bucket/mnist",
+		Workspace: "team/vision",
+		RunID:     run,
+	})
+	mlmd.SeedExecution(mlmdtest.Execution{
+		Name:      "training",
+		Workspace: "team/vision",
+		RunID:     run,
+		State:     pb.Execution_COMPLETE,
+		Inputs:    []int64{dataset},
+		Outputs:   []int64{model},
+	})
+	mlmd.SeedArtifact(mlmdtest.Artifact{
+		Type:      mlmdtest.MetricsType,
+		Name:      "MNIST-evaluation",
+		URI:       "gcs://my-bucket/mnist-eval.csv",
+		Workspace: "team/vision",
+	})
+
+	return mlmd
+}
+
+func newServer(t *testing.T, mlmd *mlmdtest.Store) *httptest.Server {
+	artifactStore, err := registry.NewArtifactStore(registry.WithClient(mlmd))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return httptest.NewServer(server.NewServer(artifactStore))
+}
+
+func get(t *testing.T, url string) (int, []byte) {
+	t.Helper()
+	response, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if contentType := response.Header.Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("GET %s Content-Type = %s", url, contentType)
+	}
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return response.StatusCode, body
+}
+
+func TestServer(t *testing.T) {
+	httpServer := newServer(t, exampleMLMD())
+	defer httpServer.Close()
+
+	tests := []struct {
+		path  string
+		names []string
+	}{
+		{"/v1/workspaces/team%2Fvision/artifacts", []string{"mnist", "MNIST", "MNIST-evaluation"}},
+		{"/v1/workspaces/team%2Fvision/artifacts?artifact_type=model", []string{"MNIST"}},
+		{"/v1/workspaces/team%2Fvision/artifacts?type_name=kubeflow.org/alpha/metrics", []string{"MNIST-evaluation"}},
+		{"/v1/workspaces/team%2Fvision/runs/run-2021-03-30T16:50:45.608098/artifacts", []string{"mnist", "MNIST"}},
+		{"/v1/workspaces/team%2Fvision/models/2/lineage", []string{"mnist", "MNIST"}},
+	}
+	for _, test := range tests {
+		statusCode, body := get(t, httpServer.URL+test.path)
+		if statusCode != http.StatusOK {
+			t.Errorf("GET %s = %d %s", test.path, statusCode, body)
+			continue
+		}
+		var artifactsResponse pb.ArtifactsResponse
+		if err := protojson.Unmarshal(body, &artifactsResponse); err != nil {
+			t.Errorf("GET %s returned an invalid ArtifactsResponse: %v", test.path, err)
+			continue
+		}
+		var names []string
+		for _, artifact := range artifactsResponse.GetArtifacts() {
+			names = append(names, artifact.GetName())
+		}
+		if fmt.Sprint(names) != fmt.Sprint(test.names) {
+			t.Errorf("GET %s returned %v, want %v", test.path, names, test.names)
+		}
+	}
+
+	statusCode, body := get(t, httpServer.URL+"/v1/workspaces/team%2Fvision")
+	var workspace pb.Workspace
+	if err := protojson.Unmarshal(body, &workspace); statusCode != http.StatusOK || err != nil || workspace.GetName() != "team/vision" {
+		t.Errorf("GET workspace = %d %s", statusCode, body)
+	}
+
+	// Fields at their default value are encoded for clients which do not know
+	// them, e.g. the MODEL artifact type
+	_, body = get(t, httpServer.URL+"/v1/workspaces/team%2Fvision/artifacts?artifact_type=MODEL")
+	var encoded struct {
+		Artifacts []map[string]interface{}
+	}
+	if err := json.Unmarshal(body, &encoded); err != nil || len(encoded.Artifacts) != 1 || encoded.Artifacts[0]["artifactType"] != "MODEL" {
+		t.Errorf("model artifacts = %s", body)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	mlmd := exampleMLMD()
+	httpServer := newServer(t, mlmd)
+	defer httpServer.Close()
+
+	tests := []struct {
+		path       string
+		statusCode int
+		code       codes.Code
+	}{
+		{"/v1/workspaces/missing/artifacts", http.StatusNotFound, codes.NotFound},
+		{"/v1/workspaces/team%2Fvision/models/0/lineage", http.StatusBadRequest, codes.InvalidArgument},
+		{"/v1/workspaces/team%2Fvision/models/MNIST/lineage", http.StatusBadRequest, codes.InvalidArgument},
+		{"/v1/workspaces/team%2Fvision/artifacts?artifact_type=CHECKPOINT", http.StatusBadRequest, codes.InvalidArgument},
+		{"/v1/workspaces/team%2Fvision/artifacts?page_size=10", http.StatusBadRequest, codes.InvalidArgument},
+		{"/v1/workspaces/team%2Fvision/executions", http.StatusNotFound, codes.NotFound},
+		{"/v1/workspaces//artifacts", http.StatusBadRequest, codes.InvalidArgument},
+		{"/v2/workspaces", http.StatusNotFound, codes.NotFound},
+	}
+	for _, test := range tests {
+		statusCode, body := get(t, httpServer.URL+test.path)
+		var st struct {
+			Code    codes.Code
+			Message string
+		}
+		if err := json.Unmarshal(body, &st); err != nil || statusCode != test.statusCode || st.Code != test.code || st.Message == "" {
+			t.Errorf("GET %s = %d %s, want %d with code %s", test.path, statusCode, body, test.statusCode, test.code)
+		}
+	}
+
+	response, err := http.Post(httpServer.URL+"/v1/workspaces/team%2Fvision/artifacts", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusMethodNotAllowed || response.Header.Get("Allow") != "GET, HEAD" {
+		t.Errorf("POST = %d, Allow: %s", response.StatusCode, response.Header.Get("Allow"))
+	}
+
+	mlmd.SetError("GetContextByTypeAndName", status.Error(codes.Unavailable, "connection refused"))
+	if statusCode, body := get(t, httpServer.URL+"/v1/workspaces/team%2Fvision"); statusCode != http.StatusServiceUnavailable {
+		t.Errorf("GET with MLMD down = %d %s", statusCode, body)
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		err        error
+		statusCode int
+	}{
+		{nil, http.StatusOK},
+		{fmt.Errorf("GetModelVersion: %w", registry.ErrNotFound), http.StatusNotFound},
+		{fmt.Errorf("artifact name is required: %w", registry.ErrInvalidArgument), http.StatusBadRequest},
+		{fmt.Errorf("alias is taken: %w", registry.ErrConflict), http.StatusConflict},
+		{registry.ErrUnavailable, http.StatusServiceUnavailable},
+		{registry.ErrTimeout, http.StatusGatewayTimeout},
+		{status.Error(codes.PermissionDenied, "denied"), http.StatusForbidden},
+		{status.Error(codes.Unauthenticated, "no token"), http.StatusUnauthorized},
+		{status.Error(codes.ResourceExhausted, "quota"), http.StatusTooManyRequests},
+		{status.Error(codes.Internal, "boom"), http.StatusInternalServerError},
+		{errors.New("unknown"), http.StatusInternalServerError},
+	}
+	for _, test := range tests {
+		if statusCode := server.HTTPStatus(test.err); statusCode != test.statusCode {
+			t.Errorf("HTTPStatus(%v) = %d, want %d", test.err, statusCode, test.statusCode)
+		}
+	}
+}